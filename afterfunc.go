@@ -0,0 +1,95 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"sort"
+)
+
+// PendingFunc describes a function registered with context.AfterFunc that has
+// not yet been run or stopped.
+type PendingFunc struct {
+	// Origin holds the name and source location of the function.
+	Origin
+
+	// Layer is the cancelable layer that the function is registered on, and
+	// Depth is its depth.
+	Layer context.Context
+	Depth int
+}
+
+// AfterFuncs will return every function registered with context.AfterFunc
+// that is still pending on a cancelable layer of the given context. Results
+// are ordered from the innermost layer to the outermost. A passed nil context
+// will return nil.
+//
+// Functions registered on a context are tracked by its nearest cancelable
+// layer, which is not necessarily the context that was passed to
+// context.AfterFunc.
+func AfterFuncs(ctx context.Context) []PendingFunc {
+	var pending []PendingFunc
+
+	layers := chain(ctx)
+	for index, layer := range layers {
+		depth := len(layers) - 1 - index
+
+		for _, fn := range layerAfterFuncs(layer) {
+			pending = append(pending, PendingFunc{
+				Origin: funcOrigin(fn),
+				Layer:  layer,
+				Depth:  depth,
+			})
+		}
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		if pending[i].Depth != pending[j].Depth {
+			return pending[i].Depth < pending[j].Depth
+		}
+		if pending[i].File != pending[j].File {
+			return pending[i].File < pending[j].File
+		}
+		return pending[i].Line < pending[j].Line
+	})
+
+	return pending
+}
+
+// layerAfterFuncs returns the functions registered on the given cancelable
+// layer, by reading the "f" field of each of its children.
+func layerAfterFuncs(ctx context.Context) []reflect.Value {
+	var funcs []reflect.Value
+
+	for _, child := range layerChildren(ctx) {
+		childVal, ok := layerStruct(child)
+		if !ok {
+			continue
+		}
+
+		fnField, ok := fieldByName(childVal, "f")
+		if !ok || fnField.Kind() != reflect.Func || fnField.IsNil() {
+			continue
+		}
+
+		funcs = append(funcs, reflect.ValueOf(readField(fnField)))
+	}
+
+	return funcs
+}
+
+// funcOrigin returns the name and source location of the given function.
+func funcOrigin(fn reflect.Value) Origin {
+	var origin Origin
+
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		origin.Function = f.Name()
+		origin.File, origin.Line = f.FileLine(f.Entry())
+	}
+
+	return origin
+}
@@ -0,0 +1,64 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func afterFuncCleanup() {}
+
+func TestAfterFuncs(t *testing.T) {
+
+	assert.Nil(t, AfterFuncs(nil))
+	assert.Nil(t, AfterFuncs(context.Background()))
+
+	root, cancelRoot := context.WithCancel(context.Background())
+	defer cancelRoot()
+	ctx := context.WithValue(root, "key", "value")
+	inner, cancelInner := context.WithCancel(ctx)
+	defer cancelInner()
+
+	context.AfterFunc(ctx, afterFuncCleanup)
+	stop := context.AfterFunc(root, func() {})
+	context.AfterFunc(inner, func() {})
+
+	// Children that are not functions are skipped
+	_, cancelChild := context.WithCancel(inner)
+	defer cancelChild()
+
+	pending := AfterFuncs(inner)
+	require.Len(t, pending, 3)
+
+	assert.Equal(t, "github.com/joshdk/contents.afterFuncCleanup", pending[0].Function)
+	assert.True(t, strings.HasSuffix(pending[0].File, "/afterfunc_test.go"), pending[0].File)
+	assert.Equal(t, root, pending[0].Layer)
+	assert.Equal(t, 1, pending[0].Depth)
+
+	assert.Equal(t, "github.com/joshdk/contents.TestAfterFuncs.func1", pending[1].Function)
+	assert.Equal(t, root, pending[1].Layer)
+
+	assert.Equal(t, "github.com/joshdk/contents.TestAfterFuncs.func2", pending[2].Function)
+	assert.Equal(t, inner, pending[2].Layer)
+	assert.Equal(t, 3, pending[2].Depth)
+
+	// Stopped functions are no longer pending
+	stop()
+
+	pending = AfterFuncs(inner)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "github.com/joshdk/contents.afterFuncCleanup", pending[0].Function)
+
+	// Neither are functions that have been run
+	cancelRoot()
+
+	assert.Nil(t, AfterFuncs(inner))
+
+}
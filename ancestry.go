@@ -0,0 +1,49 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+)
+
+// Depth will return the number of times that the given context can be
+// unwrapped. Contexts such as context.Background() have a depth of 0, as
+// does a passed nil context.
+func Depth(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+
+	return len(chain(ctx)) - 1
+}
+
+// IsAncestor will return true if the child context was derived from the parent
+// context, by repeatedly unwrapping the child. A context is considered to be
+// an ancestor of itself. A passed nil context will return false.
+func IsAncestor(parent, child context.Context) bool {
+	if parent == nil {
+		return false
+	}
+
+	for _, layer := range chain(child) {
+		if same(layer, parent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CommonAncestor will return the closest context that both of the given
+// contexts were derived from, or nil if they share no common ancestor.
+func CommonAncestor(a, b context.Context) context.Context {
+	for _, layer := range chain(a) {
+		if IsAncestor(layer, b) {
+			return layer
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshdk/contents"
+)
+
+func ExampleIsAncestor() {
+	root, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ctx := context.WithValue(root, "key a", "value a")
+
+	if contents.IsAncestor(root, ctx) {
+		fmt.Println("The context ctx was derived from root")
+	}
+	// Output:
+	// The context ctx was derived from root
+}
+
+func ExampleDepth() {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "key a", "value a")
+	ctx = context.WithValue(ctx, "key b", "value b")
+
+	fmt.Printf("The context ctx has a depth of %d\n", contents.Depth(ctx))
+	// Output:
+	// The context ctx has a depth of 2
+}
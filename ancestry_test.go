@@ -0,0 +1,159 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDepth(t *testing.T) {
+
+	tests := []struct {
+		title string
+		ctx   context.Context
+		depth int
+	}{
+		{
+			title: "nil context",
+			ctx:   nil,
+		},
+		{
+			title: "background context",
+			ctx:   context.Background(),
+		},
+		{
+			title: "cancel context",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx, cancel := context.WithCancel(ctx)
+				_ = cancel
+				return ctx
+			}(),
+			depth: 1,
+		},
+		{
+			title: "timeout context with keys",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx, cancel := context.WithTimeout(ctx, 0)
+				_ = cancel
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				return ctx
+			}(),
+			depth: 3,
+		},
+		{
+			title: "cycle of contexts",
+			ctx: func() context.Context {
+				a := &fieldContext{key: "key-a"}
+				b := &fieldContext{Context: a, key: "key-b"}
+				a.Context = b
+				return context.WithValue(a, "key-1", "value-1")
+			}(),
+			depth: 2,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.depth, Depth(test.ctx))
+
+		})
+
+	}
+
+}
+
+func TestAncestry(t *testing.T) {
+
+	root := context.WithValue(context.Background(), "root", "value")
+	left := context.WithValue(root, "left", "value")
+	leftChild, cancel := context.WithCancel(left)
+	defer cancel()
+	right := context.WithValue(root, "right", "value")
+	other := context.WithValue(context.TODO(), "other", "value")
+
+	tests := []struct {
+		title      string
+		a          context.Context
+		b          context.Context
+		isAncestor bool
+		common     context.Context
+	}{
+		{
+			title: "nil contexts",
+		},
+		{
+			title: "nil parent",
+			b:     root,
+		},
+		{
+			title: "nil child",
+			a:     root,
+		},
+		{
+			title:      "same context",
+			a:          left,
+			b:          left,
+			isAncestor: true,
+			common:     left,
+		},
+		{
+			title:      "direct parent",
+			a:          root,
+			b:          left,
+			isAncestor: true,
+			common:     root,
+		},
+		{
+			title:      "distant parent",
+			a:          root,
+			b:          leftChild,
+			isAncestor: true,
+			common:     root,
+		},
+		{
+			title:  "child",
+			a:      leftChild,
+			b:      left,
+			common: left,
+		},
+		{
+			title:  "siblings",
+			a:      leftChild,
+			b:      right,
+			common: root,
+		},
+		{
+			title: "unrelated",
+			a:     other,
+			b:     right,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.isAncestor, IsAncestor(test.a, test.b))
+			assert.Equal(t, test.common, CommonAncestor(test.a, test.b))
+			assert.Equal(t, test.common, CommonAncestor(test.b, test.a))
+
+		})
+
+	}
+
+}
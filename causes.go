@@ -0,0 +1,33 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+)
+
+// Causes will return every cancelable layer of the given context that has
+// been canceled, along with its error and cause. Layers are returned in the
+// order in which they are found by Unwrap, from the outermost to the
+// innermost. A passed nil context will return nil.
+//
+// Each layer created with context.WithCancelCause, context.WithDeadlineCause,
+// and friends may carry a different cause, so the returned layers describe
+// the whole chain of events that canceled the context, such as a deadline
+// being exceeded on an outer layer after an inner layer was canceled because
+// of a shutdown.
+func Causes(ctx context.Context) []Layer {
+	var causes []Layer
+
+	layers := Snapshot(ctx).Layers
+
+	for index := len(layers) - 1; index >= 0; index-- {
+		if layers[index].Cancelable && layers[index].Err != nil {
+			causes = append(causes, layers[index])
+		}
+	}
+
+	return causes
+}
@@ -0,0 +1,101 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCauses(t *testing.T) {
+
+	shutdown := errors.New("upstream shutdown")
+	slow := errors.New("client gave up")
+
+	type cause struct {
+		typ   string
+		err   error
+		cause error
+	}
+
+	tests := []struct {
+		title  string
+		ctx    context.Context
+		causes []cause
+	}{
+		{
+			title: "nil context",
+			ctx:   nil,
+		},
+		{
+			title: "background context",
+			ctx:   context.Background(),
+		},
+		{
+			title: "cancel context",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx, cancel := context.WithCancel(ctx)
+				_ = cancel
+				return ctx
+			}(),
+		},
+		{
+			title: "canceled cancel context",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				ctx = context.WithValue(ctx, "key", "value")
+				return ctx
+			}(),
+			causes: []cause{
+				{"*context.cancelCtx", context.Canceled, context.Canceled},
+			},
+		},
+		{
+			title: "chain of causes",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx, cancel := context.WithCancelCause(ctx)
+				ctx = context.WithValue(ctx, "key", "value")
+				ctx, cancelTimeout := context.WithTimeoutCause(ctx, time.Hour, slow)
+				_ = cancelTimeout
+				ctx, cancelDeadline := context.WithDeadlineCause(ctx, time.Now().Add(-time.Second), slow)
+				_ = cancelDeadline
+				cancel(shutdown)
+				return ctx
+			}(),
+			causes: []cause{
+				{"*context.timerCtx", context.DeadlineExceeded, slow},
+				{"*context.timerCtx", context.Canceled, shutdown},
+				{"*context.cancelCtx", context.Canceled, shutdown},
+			},
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			var causes []cause
+			for _, layer := range Causes(test.ctx) {
+				causes = append(causes, cause{layer.Type, layer.Err, layer.Cause})
+			}
+
+			assert.Equal(t, test.causes, causes)
+
+		})
+
+	}
+
+}
@@ -0,0 +1,194 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Codec describes how the value for a single context key is converted to and
+// from bytes.
+type Codec struct {
+	Encode func(value interface{}) ([]byte, error)
+	Decode func(data []byte) (interface{}, error)
+}
+
+// JSONCodec returns a codec that encodes values of type T as JSON.
+func JSONCodec[T any]() Codec {
+	return Codec{
+		Encode: func(value interface{}) ([]byte, error) {
+			return json.Marshal(value)
+		},
+		Decode: func(data []byte) (interface{}, error) {
+			var value T
+			if err := json.Unmarshal(data, &value); err != nil {
+				return nil, err
+			}
+			return value, nil
+		},
+	}
+}
+
+type registeredCodec struct {
+	key   interface{}
+	name  string
+	codec Codec
+}
+
+var (
+	codecsMu     sync.RWMutex
+	codecsByKey  = map[interface{}]registeredCodec{}
+	codecsByName = map[string]registeredCodec{}
+)
+
+// RegisterCodec registers a codec for the given key. Values for this key will
+// be included by Encode, and restored by Decode. The given name identifies the
+// key inside of an encoded envelope, and so must be the same in every process
+// that exchanges envelopes. Registering a key or name again replaces its
+// previous registration.
+//
+// As with context.WithValue, the given key must be comparable.
+func RegisterCodec(key interface{}, name string, codec Codec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+
+	// Remove any previous registration for this key or name
+	if previous, found := codecsByKey[key]; found {
+		delete(codecsByName, previous.name)
+	}
+	if previous, found := codecsByName[name]; found {
+		delete(codecsByKey, previous.key)
+	}
+
+	registered := registeredCodec{
+		key:   key,
+		name:  name,
+		codec: codec,
+	}
+
+	codecsByKey[key] = registered
+	codecsByName[name] = registered
+}
+
+// lookupCodec returns the codec registered for the given key, and if such a
+// codec exists.
+func lookupCodec(key interface{}) (registeredCodec, bool) {
+	// Guard against keys that can not be used in a map
+	if key == nil || !reflect.TypeOf(key).Comparable() {
+		return registeredCodec{}, false
+	}
+
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	registered, found := codecsByKey[key]
+	return registered, found
+}
+
+// lookupCodecName returns the codec registered with the given name, and if such
+// a codec exists.
+func lookupCodecName(name string) (registeredCodec, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	registered, found := codecsByName[name]
+	return registered, found
+}
+
+// registeredCodecs returns every registered codec, ordered by name.
+func registeredCodecs() []registeredCodec {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	registered := make([]registeredCodec, 0, len(codecsByName))
+	for _, codec := range codecsByName {
+		registered = append(registered, codec)
+	}
+
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].name < registered[j].name
+	})
+
+	return registered
+}
+
+// envelopeValue is a single encoded key:value pair inside of an envelope.
+type envelopeValue struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// envelope is the portable form of a context's values.
+type envelope struct {
+	Values []envelopeValue `json:"values"`
+}
+
+// Encode will return a portable envelope containing the value of every key
+// within the context that has a registered codec. Shadowed values are not
+// included. Keys without a registered codec are skipped.
+func Encode(ctx context.Context) ([]byte, error) {
+	var env envelope
+
+	for _, pair := range live(Pairs(ctx)) {
+		registered, found := lookupCodec(pair.Key)
+		if !found {
+			continue
+		}
+
+		data, err := registered.codec.Encode(pair.Value)
+		if err != nil {
+			return nil, fmt.Errorf("encoding %q: %w", registered.name, err)
+		}
+
+		env.Values = append(env.Values, envelopeValue{
+			Key:   registered.name,
+			Value: data,
+		})
+	}
+
+	return json.Marshal(env)
+}
+
+// Decode will return a context derived from base that contains every value
+// held in the given envelope, in the order in which they were encoded. Values
+// whose key name has no registered codec in this process are skipped. A
+// passed nil base is treated as context.Background().
+func Decode(base context.Context, data []byte) (context.Context, error) {
+	if base == nil {
+		base = context.Background()
+	}
+
+	var env envelope
+
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("decoding envelope: %w", err)
+	}
+
+	var pairs []Pair
+
+	for _, encoded := range env.Values {
+		registered, found := lookupCodecName(encoded.Key)
+		if !found {
+			continue
+		}
+
+		value, err := registered.codec.Decode(encoded.Value)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %q: %w", encoded.Key, err)
+		}
+
+		pairs = append(pairs, Pair{
+			Key:   registered.key,
+			Value: value,
+		})
+	}
+
+	return rebuild(base, pairs), nil
+}
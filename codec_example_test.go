@@ -0,0 +1,35 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshdk/contents"
+)
+
+type tenantKey struct{}
+
+func ExampleEncode() {
+	contents.RegisterCodec(tenantKey{}, "tenant", contents.JSONCodec[string]())
+
+	// In the HTTP handler
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	data, err := contents.Encode(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	// In the async worker
+	restored, err := contents.Decode(context.Background(), data)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Value of tenant is %q\n", restored.Value(tenantKey{}))
+	// Output:
+	// Value of tenant is "acme"
+}
@@ -0,0 +1,182 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type codecKey string
+
+func TestEncodeDecode(t *testing.T) {
+
+	RegisterCodec(codecKey("tenant"), "tenant", JSONCodec[string]())
+	RegisterCodec(codecKey("flags"), "flags", JSONCodec[[]string]())
+	RegisterCodec(codecKey("broken"), "broken", Codec{
+		Encode: func(value interface{}) ([]byte, error) {
+			return nil, errors.New("broken")
+		},
+	})
+
+	tests := []struct {
+		title string
+		ctx   context.Context
+		pairs []Pair
+		err   string
+	}{
+		{
+			title: "nil context",
+			ctx:   nil,
+		},
+		{
+			title: "background context",
+			ctx:   context.Background(),
+		},
+		{
+			title: "unregistered keys",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "tenant", "value")
+				ctx = context.WithValue(ctx, codecKey("other"), "value")
+				return ctx
+			}(),
+		},
+		{
+			title: "registered keys",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, codecKey("tenant"), "acme")
+				ctx = context.WithValue(ctx, "other", "value")
+				ctx = context.WithValue(ctx, codecKey("flags"), []string{"a", "b"})
+				ctx = context.WithValue(ctx, codecKey("tenant"), "initech")
+				return ctx
+			}(),
+			pairs: []Pair{
+				{codecKey("flags"), []string{"a", "b"}},
+				{codecKey("tenant"), "initech"},
+			},
+		},
+		{
+			title: "encoding error",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, codecKey("broken"), "value")
+				return ctx
+			}(),
+			err: `encoding "broken": broken`,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			data, err := Encode(test.ctx)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+
+			ctx, err := Decode(context.Background(), data)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.pairs, Pairs(ctx))
+
+		})
+
+	}
+
+}
+
+func TestDecode(t *testing.T) {
+
+	RegisterCodec(codecKey("count"), "count", JSONCodec[int]())
+
+	tests := []struct {
+		title string
+		base  context.Context
+		data  string
+		pairs []Pair
+		err   string
+	}{
+		{
+			title: "nil base",
+			data:  `{"values":[{"key":"count","value":"MQ=="}]}`,
+			pairs: []Pair{
+				{codecKey("count"), 1},
+			},
+		},
+		{
+			title: "existing values",
+			base:  context.WithValue(context.Background(), "key", "value"),
+			data:  `{"values":[{"key":"count","value":"MQ=="}]}`,
+			pairs: []Pair{
+				{"key", "value"},
+				{codecKey("count"), 1},
+			},
+		},
+		{
+			title: "unknown key",
+			base:  context.Background(),
+			data:  `{"values":[{"key":"unknown","value":"MQ=="}]}`,
+		},
+		{
+			title: "malformed envelope",
+			base:  context.Background(),
+			data:  `[]`,
+			err:   "decoding envelope: json: cannot unmarshal array into Go value of type contents.envelope",
+		},
+		{
+			title: "malformed value",
+			base:  context.Background(),
+			data:  `{"values":[{"key":"count","value":"Im9uZSI="}]}`,
+			err:   `decoding "count": json: cannot unmarshal string into Go value of type int`,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			ctx, err := Decode(test.base, []byte(test.data))
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				assert.Nil(t, ctx)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.pairs, Pairs(ctx))
+
+		})
+
+	}
+
+}
+
+func TestRegisterCodecReplace(t *testing.T) {
+
+	RegisterCodec(codecKey("first"), "replaced", JSONCodec[string]())
+	RegisterCodec(codecKey("second"), "replaced", JSONCodec[string]())
+
+	_, found := lookupCodec(codecKey("first"))
+	assert.False(t, found)
+
+	registered, found := lookupCodecName("replaced")
+	assert.True(t, found)
+	assert.Equal(t, codecKey("second"), registered.key)
+
+}
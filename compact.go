@@ -0,0 +1,85 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"reflect"
+)
+
+// Compact returns a context that resolves the same values, and has the same
+// deadline and cancellation, as the given context, but with only one layer
+// per distinct key. Shadowed values are discarded, and the remaining keys
+// retain the order in which their current values were added. A passed nil
+// context will return nil.
+//
+// Looking up a value with ".Value(key)" costs time proportional to the number
+// of layers it has to pass through, so compacting a deeply wrapped context
+// that is read often can be worthwhile.
+//
+// Only lookups for keys found by Pairs are sped up. Lookups for any other key,
+// including keys that are missing, fall back to the given context and pass
+// through all of its layers. The given context is kept alive by the returned
+// context for this reason, along with every value that it holds, including
+// shadowed values.
+func Compact(ctx context.Context) context.Context {
+	if ctx == nil {
+		return nil
+	}
+
+	pairs := live(Pairs(ctx))
+
+	// Resolve each value through the given context, as a custom context may
+	// answer a key differently from the layer that holds it
+	for index := range pairs {
+		pairs[index].Value = ctx.Value(pairs[index].Key)
+	}
+
+	return rebuild(&opaqueCtx{cancelOnlyCtx{ctx}}, pairs)
+}
+
+// live returns the given pairs with every shadowed pair removed. A pair is
+// shadowed if a later pair has the same key. Pairs with keys that can not be
+// used in a map, such as those reported for some custom contexts, are also
+// removed.
+func live(pairs []Pair) []Pair {
+	var (
+		seen   = map[interface{}]bool{}
+		result []Pair
+	)
+
+	// Walk backwards, so that the first occurrence of a key is its live value
+	for index := len(pairs) - 1; index >= 0; index-- {
+		// Guard against keys that can not be used in a map
+		if key := pairs[index].Key; key != nil && !reflect.TypeOf(key).Comparable() {
+			continue
+		}
+
+		if seen[pairs[index].Key] {
+			continue
+		}
+		seen[pairs[index].Key] = true
+		result = append(result, pairs[index])
+	}
+
+	// Restore the original order
+	for left, right := 0, len(result)-1; left < right; left, right = left+1, right-1 {
+		result[left], result[right] = result[right], result[left]
+	}
+
+	return result
+}
+
+// opaqueCtx is a context that behaves exactly like the wrapped context, but
+// can not be unwrapped. Values that are only reachable through the wrapped
+// context, such as those held by custom context implementations, remain
+// visible to ".Value(key)" but not to Keys or Pairs.
+type opaqueCtx struct {
+	cancelOnlyCtx
+}
+
+func (c *opaqueCtx) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
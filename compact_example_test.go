@@ -0,0 +1,28 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshdk/contents"
+)
+
+func ExampleCompact() {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "key a", "value a")
+	ctx = context.WithValue(ctx, "key b", "value b")
+	ctx = context.WithValue(ctx, "key a", "VALUE A")
+
+	pairs := contents.Pairs(contents.Compact(ctx))
+
+	for index, pair := range pairs {
+		fmt.Printf("Value of pairs[%d] is %q\n", index, pair)
+	}
+	// Output:
+	// Value of pairs[0] is {"key b" "value b"}
+	// Value of pairs[1] is {"key a" "VALUE A"}
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompact(t *testing.T) {
+
+	tests := []struct {
+		title string
+		ctx   context.Context
+		pairs []Pair
+		err   error
+	}{
+		{
+			title: "nil context",
+			ctx:   nil,
+		},
+		{
+			title: "background context",
+			ctx:   context.Background(),
+		},
+		{
+			title: "distinct keys",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				return ctx
+			}(),
+			pairs: []Pair{
+				{"key-1", "value-1"},
+				{"key-2", "value-2"},
+			},
+		},
+		{
+			title: "duplicate keys",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				ctx = context.WithValue(ctx, "key-3", "value-3")
+				ctx = context.WithValue(ctx, "key-1", "VALUE-ONE")
+				ctx = context.WithValue(ctx, "key-2", "VALUE-TWO")
+				ctx = context.WithValue(ctx, "key-1", "value-one")
+				return ctx
+			}(),
+			pairs: []Pair{
+				{"key-3", "value-3"},
+				{"key-2", "VALUE-TWO"},
+				{"key-1", "value-one"},
+			},
+		},
+		{
+			title: "canceled context with keys",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				ctx = context.WithValue(ctx, "key-1", "VALUE-ONE")
+				return ctx
+			}(),
+			pairs: []Pair{
+				{"key-1", "VALUE-ONE"},
+			},
+			err: context.Canceled,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			ctx := Compact(test.ctx)
+
+			if test.ctx == nil {
+				assert.Nil(t, ctx)
+				return
+			}
+
+			assert.Equal(t, test.pairs, Pairs(ctx))
+			assert.Equal(t, test.err, ctx.Err())
+			assert.Equal(t, Map(test.ctx), Map(ctx))
+
+			for _, key := range Keys(test.ctx) {
+				assert.Equal(t, test.ctx.Value(key), ctx.Value(key))
+			}
+
+		})
+
+	}
+
+}
+
+func TestCompactCustomValue(t *testing.T) {
+
+	ctx := context.WithValue(&customValueContext{context.Background()}, "key", "value")
+
+	compacted := Compact(ctx)
+
+	assert.Equal(t, "value", compacted.Value("key"))
+	assert.Equal(t, "custom", compacted.Value("custom"))
+
+}
+
+func TestCompactShadowingCustomValue(t *testing.T) {
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "custom", "original")
+	ctx = &customValueContext{ctx}
+	ctx = context.WithValue(ctx, "key", "value")
+
+	compacted := Compact(ctx)
+
+	assert.Equal(t, "custom", ctx.Value("custom"))
+	assert.Equal(t, "custom", compacted.Value("custom"))
+	assert.Equal(t, "custom", Flatten(ctx).Value("custom"))
+	assert.Equal(t, []Pair{{"custom", "custom"}, {"key", "value"}}, Pairs(compacted))
+
+}
+
+type customValueContext struct {
+	context.Context
+}
+
+func (c *customValueContext) Value(key interface{}) interface{} {
+	if key == "custom" {
+		return "custom"
+	}
+	return c.Context.Value(key)
+}
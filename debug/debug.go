@@ -0,0 +1,272 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package debug provides an HTTP handler that lists the contexts of every
+// in-flight request, in the style of net/http/pprof.
+//
+// Requests are tracked by wrapping a handler with Middleware. Importing this
+// package registers the Index handler on http.DefaultServeMux at the path
+// "/debug/contexts".
+//
+//	import "github.com/joshdk/contents/debug"
+//
+//	http.ListenAndServe(":8080", debug.Middleware(handler))
+//
+// By default the listing is rendered as plain text. Adding the query
+// parameter "format=json" renders it as JSON instead.
+//
+// # Security
+//
+// Importing this package exposes the listing to anyone who can reach
+// http.DefaultServeMux. The listing includes the method and URI of every
+// in-flight request, and the key and type of every value in its context.
+// Only serve http.DefaultServeMux on a port that is not reachable by
+// untrusted callers.
+//
+// Values themselves are not shown unless a renderer is set with
+// RenderValues. Renderers are called while the requests that own the values
+// are still running, so they must only read values that are safe to read
+// concurrently, and should redact credentials and other secrets.
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/trace"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/joshdk/contents"
+)
+
+func init() {
+	http.HandleFunc("/debug/contexts", Index)
+}
+
+// tracked is a single context that is currently being tracked.
+type tracked struct {
+	ctx     context.Context
+	name    string
+	started time.Time
+}
+
+var (
+	trackedMu sync.Mutex
+	trackedID uint64
+	trackedBy = map[uint64]tracked{}
+)
+
+// Track registers the given context under the given name, until the returned
+// function is called. This can be used to list contexts that do not belong to
+// an HTTP request, such as those of background jobs.
+func Track(ctx context.Context, name string) (untrack func()) {
+	trackedMu.Lock()
+	defer trackedMu.Unlock()
+
+	trackedID++
+	id := trackedID
+
+	trackedBy[id] = tracked{
+		ctx:     ctx,
+		name:    name,
+		started: time.Now(),
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			trackedMu.Lock()
+			defer trackedMu.Unlock()
+			delete(trackedBy, id)
+		})
+	}
+}
+
+// Middleware returns a handler that tracks the context of each request for as
+// long as the given handler is serving it.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		untrack := Track(r.Context(), r.Method+" "+r.URL.RequestURI())
+		defer untrack()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Pair is a single key:value pair, rendered for display. Value is only set if
+// a renderer was set with RenderValues, and Origin holds where the pair was
+// set, if that is known.
+type Pair struct {
+	Key    string `json:"key"`
+	Type   string `json:"type"`
+	Value  string `json:"value,omitempty"`
+	Origin string `json:"origin,omitempty"`
+}
+
+// Renderer renders the value held by a key for display. Values that should
+// not be shown, such as credentials, can be redacted by returning a
+// placeholder instead.
+type Renderer func(key, value interface{}) string
+
+var renderer atomic.Pointer[Renderer]
+
+// RenderValues sets the renderer used to show the value of every pair in the
+// listing. Values are not shown by default, or if the given renderer is nil.
+//
+// The renderer is called while the request that owns a value may still be
+// modifying it, so it must only read values that are safe to read
+// concurrently.
+func RenderValues(render Renderer) {
+	if render == nil {
+		renderer.Store(nil)
+		return
+	}
+	renderer.Store(&render)
+}
+
+// Context describes a single tracked context.
+type Context struct {
+	Name     string         `json:"name"`
+	Age      time.Duration  `json:"age"`
+	Depth    int            `json:"depth"`
+	Deadline *time.Duration `json:"deadline,omitempty"`
+	Err      string         `json:"err,omitempty"`
+	Task     string         `json:"task,omitempty"`
+	Pairs    []Pair         `json:"pairs"`
+}
+
+// Contexts returns a description of every tracked context, oldest first.
+func Contexts() []Context {
+	trackedMu.Lock()
+	entries := make([]tracked, 0, len(trackedBy))
+	for _, entry := range trackedBy {
+		entries = append(entries, entry)
+	}
+	trackedMu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].started.Before(entries[j].started)
+	})
+
+	now := time.Now()
+	contexts := make([]Context, 0, len(entries))
+
+	for _, entry := range entries {
+		described := Context{
+			Name:  entry.name,
+			Age:   now.Sub(entry.started),
+			Depth: contents.Depth(entry.ctx),
+		}
+
+		if deadline, ok := entry.ctx.Deadline(); ok {
+			remaining := deadline.Sub(now)
+			described.Deadline = &remaining
+		}
+
+		if err := entry.ctx.Err(); err != nil {
+			described.Err = err.Error()
+		}
+
+		if task, ok := contents.TraceTask(entry.ctx); ok {
+			described.Task = task.String()
+		}
+
+		described.Pairs = pairs(entry.ctx)
+
+		contexts = append(contexts, described)
+	}
+
+	return contexts
+}
+
+// pairs returns every key:value pair contained within the context, along with
+// the origin of the layer that holds it, in the order in which they were
+// originally added.
+func pairs(ctx context.Context) []Pair {
+	pairs := []Pair{}
+	render := renderer.Load()
+
+	// Walk no more layers than the context has, as contexts that refer to each
+	// other in a cycle could otherwise be unwrapped forever
+	layer := ctx
+	for remaining := contents.Depth(ctx); layer != nil && remaining >= 0; remaining-- {
+		current := layer
+		layer = contents.Unwrap(layer)
+
+		key, found := contents.Key(current)
+		if !found {
+			continue
+		}
+
+		value := current.Value(key)
+
+		pair := Pair{
+			Key:  contents.KeyName(key),
+			Type: fmt.Sprintf("%T", value),
+		}
+
+		// Render execution trace tasks by their name and ID
+		if _, ok := value.(*trace.Task); ok {
+			if task, ok := contents.TraceTask(current); ok {
+				value = task
+			}
+		}
+
+		if render != nil {
+			pair.Value = (*render)(key, value)
+		}
+
+		if origin, found := contents.OriginOf(current); found {
+			pair.Origin = origin.String()
+		}
+
+		pairs = append([]Pair{pair}, pairs...)
+	}
+
+	return pairs
+}
+
+// Index responds with a listing of every tracked context.
+func Index(w http.ResponseWriter, r *http.Request) {
+	contexts := Contexts()
+
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(contexts)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	fmt.Fprintf(w, "%d contexts\n", len(contexts))
+
+	for _, ctx := range contexts {
+		fmt.Fprintf(w, "\n%s\n", ctx.Name)
+		fmt.Fprintf(w, "  age:      %s\n", ctx.Age)
+		fmt.Fprintf(w, "  depth:    %d\n", ctx.Depth)
+		if ctx.Deadline != nil {
+			fmt.Fprintf(w, "  deadline: %s remaining\n", *ctx.Deadline)
+		}
+		if ctx.Err != "" {
+			fmt.Fprintf(w, "  err:      %s\n", ctx.Err)
+		}
+		if ctx.Task != "" {
+			fmt.Fprintf(w, "  task:     %s\n", ctx.Task)
+		}
+		for _, pair := range ctx.Pairs {
+			fmt.Fprintf(w, "  %s (%s)", pair.Key, pair.Type)
+			if pair.Value != "" {
+				fmt.Fprintf(w, " → %s", pair.Value)
+			}
+			if pair.Origin != "" {
+				fmt.Fprintf(w, " (set at %s)", pair.Origin)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/joshdk/contents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrack(t *testing.T) {
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "key-1", "value-1")
+	ctx, cancel := context.WithTimeout(ctx, time.Hour)
+	defer cancel()
+	ctx = context.WithValue(ctx, "key-2", 2)
+
+	untrack := Track(ctx, "job")
+
+	contexts := Contexts()
+	require.Len(t, contexts, 1)
+
+	assert.Equal(t, "job", contexts[0].Name)
+	assert.Equal(t, 3, contexts[0].Depth)
+	assert.Equal(t, "", contexts[0].Err)
+	assert.Equal(t, []Pair{{"key-1", "string", "", ""}, {"key-2", "int", "", ""}}, contexts[0].Pairs)
+	require.NotNil(t, contexts[0].Deadline)
+	assert.InDelta(t, time.Hour, *contexts[0].Deadline, float64(time.Minute))
+
+	// Values are only shown once a renderer is set, which can redact them
+	RenderValues(func(key, value interface{}) string {
+		if key == "key-1" {
+			return "[redacted]"
+		}
+		return fmt.Sprint(value)
+	})
+	defer RenderValues(nil)
+
+	contexts = Contexts()
+	require.Len(t, contexts, 1)
+	assert.Equal(t, []Pair{{"key-1", "string", "[redacted]", ""}, {"key-2", "int", "2", ""}}, contexts[0].Pairs)
+
+	untrack()
+	untrack()
+
+	assert.Empty(t, Contexts())
+
+}
+
+func TestMiddleware(t *testing.T) {
+
+	var (
+		text       string
+		structured []Context
+	)
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := httptest.NewRecorder()
+		Index(recorder, httptest.NewRequest(http.MethodGet, "/debug/contexts", nil))
+		text = recorder.Body.String()
+
+		recorder = httptest.NewRecorder()
+		Index(recorder, httptest.NewRequest(http.MethodGet, "/debug/contexts?format=json", nil))
+		assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &structured))
+	}))
+
+	contents.TrackOrigins(true)
+	defer contents.TrackOrigins(false)
+
+	RenderValues(func(key, value interface{}) string {
+		return fmt.Sprint(value)
+	})
+	defer RenderValues(nil)
+
+	ctx := contents.WithValue(context.Background(), "user", "alice")
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	req := httptest.NewRequest(http.MethodGet, "/slow?id=1", nil).WithContext(ctx)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.True(t, strings.HasPrefix(text, "1 contexts\n\nGET /slow?id=1\n"), text)
+	assert.Contains(t, text, "  depth:    2\n")
+	assert.Contains(t, text, "  err:      context canceled\n")
+	assert.Contains(t, text, "  user (string) → alice (set at debug/debug_test.go:")
+	assert.NotContains(t, text, "deadline")
+
+	require.Len(t, structured, 1)
+	assert.Equal(t, "GET /slow?id=1", structured[0].Name)
+	require.Len(t, structured[0].Pairs, 1)
+	assert.Equal(t, "user", structured[0].Pairs[0].Key)
+	assert.Equal(t, "alice", structured[0].Pairs[0].Value)
+	assert.Contains(t, structured[0].Pairs[0].Origin, "debug/debug_test.go:")
+
+	assert.Empty(t, Contexts())
+
+}
+
+func TestTrackTraceTask(t *testing.T) {
+
+	ctx, task := contents.NewTask(context.Background(), "checkout")
+	defer task.End()
+
+	info, found := contents.TraceTask(ctx)
+	require.True(t, found)
+
+	untrack := Track(ctx, "job")
+	defer untrack()
+
+	contexts := Contexts()
+	require.Len(t, contexts, 1)
+
+	assert.Equal(t, info.String(), contexts[0].Task)
+	assert.Equal(t, []Pair{{"trace task", "*trace.Task", "", ""}}, contexts[0].Pairs)
+
+	RenderValues(func(key, value interface{}) string {
+		return fmt.Sprint(value)
+	})
+	defer RenderValues(nil)
+
+	contexts = Contexts()
+	require.Len(t, contexts, 1)
+	assert.Equal(t, []Pair{{"trace task", "*trace.Task", info.String(), ""}}, contexts[0].Pairs)
+
+	recorder := httptest.NewRecorder()
+	Index(recorder, httptest.NewRequest(http.MethodGet, "/debug/contexts", nil))
+
+	assert.Contains(t, recorder.Body.String(), "  task:     checkout (id ")
+
+}
+
+// cycleContext holds its parent and a key in fields, but does not delegate
+// any of its methods to its parent.
+type cycleContext struct {
+	Context context.Context
+	key     interface{}
+}
+
+func (*cycleContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (*cycleContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (*cycleContext) Err() error {
+	return nil
+}
+
+func (*cycleContext) Value(key interface{}) interface{} {
+	return nil
+}
+
+func TestTrackCycle(t *testing.T) {
+
+	a := &cycleContext{key: "key-a"}
+	a.Context = &cycleContext{Context: a, key: "key-b"}
+
+	untrack := Track(a, "cycle")
+	defer untrack()
+
+	contexts := Contexts()
+	require.Len(t, contexts, 1)
+
+	assert.Equal(t, 1, contexts[0].Depth)
+	assert.Equal(t, []Pair{{"key-b", "<nil>", "", ""}, {"key-a", "<nil>", "", ""}}, contexts[0].Pairs)
+
+}
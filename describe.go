@@ -0,0 +1,80 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Description holds the display name and description of a context key.
+type Description struct {
+	Name        string
+	Description string
+}
+
+var (
+	descriptionsMu sync.RWMutex
+	descriptions   = map[interface{}]Description{}
+)
+
+// Describe registers a display name and description for the given key. This
+// name is used in place of the key itself by any helper that renders keys,
+// such as KeyName and LogValue. Describing a key again replaces its previous
+// description.
+//
+// As with context.WithValue, the given key must be comparable.
+func Describe(key interface{}, name, description string) {
+	descriptionsMu.Lock()
+	defer descriptionsMu.Unlock()
+
+	descriptions[key] = Description{
+		Name:        name,
+		Description: description,
+	}
+}
+
+// Described returns the description registered for the given key, and if such
+// a description exists.
+func Described(key interface{}) (Description, bool) {
+	// Guard against keys that can not be used in a map
+	if key == nil || !reflect.TypeOf(key).Comparable() {
+		return Description{}, false
+	}
+
+	descriptionsMu.RLock()
+	defer descriptionsMu.RUnlock()
+
+	description, found := descriptions[key]
+	return description, found
+}
+
+// KeyName returns a human readable name for the given key. Names registered
+// with Describe take precedence, followed by keys that implement fmt.Stringer.
+// Keys of a basic type, such as strings and integers, are printed as is, and
+// all other keys are printed along with their type.
+func KeyName(key interface{}) string {
+	if description, found := Described(key); found {
+		return description.Name
+	}
+
+	switch key := key.(type) {
+	case nil:
+		return "<nil>"
+	case fmt.Stringer:
+		return key.String()
+	}
+
+	switch reflect.TypeOf(key).Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(key)
+	default:
+		return fmt.Sprintf("%#v", key)
+	}
+}
@@ -0,0 +1,27 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshdk/contents"
+)
+
+type userIDKey struct{}
+
+func ExampleDescribe() {
+	contents.Describe(userIDKey{}, "user ID", "set by auth middleware")
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, userIDKey{}, "alice")
+
+	for _, pair := range contents.Pairs(ctx) {
+		fmt.Printf("Context contains %s → %q\n", contents.KeyName(pair.Key), pair.Value)
+	}
+	// Output:
+	// Context contains user ID → "alice"
+}
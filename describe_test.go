@@ -0,0 +1,106 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type describedKey struct{}
+
+type undescribedKey struct{}
+
+type stringerKey int
+
+func (stringerKey) String() string {
+	return "stringer key"
+}
+
+func TestKeyName(t *testing.T) {
+
+	Describe(describedKey{}, "described key", "a key used in tests")
+
+	tests := []struct {
+		title       string
+		key         interface{}
+		name        string
+		description *Description
+	}{
+		{
+			title: "nil key",
+			key:   nil,
+			name:  "<nil>",
+		},
+		{
+			title: "string key",
+			key:   "key",
+			name:  "key",
+		},
+		{
+			title: "int key",
+			key:   9001,
+			name:  "9001",
+		},
+		{
+			title: "typed string key",
+			key:   typedKey("key"),
+			name:  "key",
+		},
+		{
+			title: "stringer key",
+			key:   stringerKey(0),
+			name:  "stringer key",
+		},
+		{
+			title: "struct key",
+			key:   undescribedKey{},
+			name:  "contents.undescribedKey{}",
+		},
+		{
+			title: "array key",
+			key:   [1]string{"key"},
+			name:  `[1]string{"key"}`,
+		},
+		{
+			title: "slice key",
+			key:   []string{"key"},
+			name:  `[]string{"key"}`,
+		},
+		{
+			title: "described key",
+			key:   describedKey{},
+			name:  "described key",
+			description: &Description{
+				Name:        "described key",
+				Description: "a key used in tests",
+			},
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.name, KeyName(test.key))
+
+			description, found := Described(test.key)
+			if test.description == nil {
+				assert.False(t, found)
+				return
+			}
+
+			assert.True(t, found)
+			assert.Equal(t, *test.description, description)
+
+		})
+
+	}
+
+}
@@ -0,0 +1,154 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// Change describes a key whose value differs between two contexts.
+type Change struct {
+	Key      interface{}
+	OldValue interface{}
+	NewValue interface{}
+}
+
+// State describes the deadline and cancellation state of a context.
+type State struct {
+	Deadline    time.Time
+	HasDeadline bool
+	Err         error
+}
+
+// Difference describes how one context differs from another.
+type Difference struct {
+	// Ancestor is the closest context that both contexts were derived from,
+	// or nil if they share no common layer.
+	Ancestor context.Context
+
+	// Inserted contains the layers that were wrapped around Ancestor to
+	// produce the new context, in the order in which they were added.
+	Inserted []context.Context
+
+	// Dropped contains the layers that were wrapped around Ancestor to
+	// produce the old context, in the order in which they were added.
+	Dropped []context.Context
+
+	// Added contains the pairs whose key is only present in the new context.
+	Added []Pair
+
+	// Removed contains the pairs whose key is only present in the old context.
+	Removed []Pair
+
+	// Changed contains the keys whose value differs between both contexts.
+	Changed []Change
+
+	// Before and After contain the state of the old and new context.
+	Before State
+	After  State
+}
+
+// Diff will return a description of how the context b differs from the
+// context a. Keys are compared using the value that each context would
+// resolve them to, and are reported in the order in which they were
+// originally added.
+//
+// This is useful in tests for asserting exactly what a piece of middleware
+// added to a context.
+func Diff(a, b context.Context) Difference {
+	diff := Difference{
+		Ancestor: CommonAncestor(a, b),
+		Before:   state(a),
+		After:    state(b),
+	}
+
+	diff.Inserted = above(b, diff.Ancestor)
+	diff.Dropped = above(a, diff.Ancestor)
+
+	oldPairs := live(Pairs(a))
+	newPairs := live(Pairs(b))
+
+	oldValues := map[interface{}]interface{}{}
+	for _, pair := range oldPairs {
+		oldValues[pair.Key] = pair.Value
+	}
+
+	newValues := map[interface{}]interface{}{}
+	for _, pair := range newPairs {
+		newValues[pair.Key] = pair.Value
+	}
+
+	for _, pair := range newPairs {
+		oldValue, found := oldValues[pair.Key]
+		switch {
+		case !found:
+			diff.Added = append(diff.Added, pair)
+		case !equal(oldValue, pair.Value):
+			diff.Changed = append(diff.Changed, Change{
+				Key:      pair.Key,
+				OldValue: oldValue,
+				NewValue: pair.Value,
+			})
+		}
+	}
+
+	for _, pair := range oldPairs {
+		if _, found := newValues[pair.Key]; !found {
+			diff.Removed = append(diff.Removed, pair)
+		}
+	}
+
+	return diff
+}
+
+// state returns the deadline and cancellation state of the given context.
+func state(ctx context.Context) State {
+	if ctx == nil {
+		return State{}
+	}
+
+	deadline, ok := ctx.Deadline()
+
+	return State{
+		Deadline:    deadline,
+		HasDeadline: ok,
+		Err:         ctx.Err(),
+	}
+}
+
+// above returns every layer of the given context that wraps the given
+// ancestor, in the order in which they were added.
+func above(ctx context.Context, ancestor context.Context) []context.Context {
+	var layers []context.Context
+
+	for _, layer := range chain(ctx) {
+		if same(layer, ancestor) {
+			break
+		}
+		layers = append([]context.Context{layer}, layers...)
+	}
+
+	return layers
+}
+
+// equal reports if both values are equal, falling back to a deep comparison
+// for values that are not comparable.
+func equal(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+
+	if reflect.TypeOf(a).Comparable() {
+		return identical(a, b)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
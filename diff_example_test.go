@@ -0,0 +1,35 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshdk/contents"
+)
+
+func ExampleDiff() {
+	before := context.Background()
+	before = context.WithValue(before, "user", "alice")
+	before = context.WithValue(before, "role", "viewer")
+
+	after := context.WithValue(before, "role", "admin")
+	after = context.WithValue(after, "request-id", "abc123")
+
+	diff := contents.Diff(before, after)
+
+	for _, pair := range diff.Added {
+		fmt.Printf("Added %q → %q\n", pair.Key, pair.Value)
+	}
+	for _, change := range diff.Changed {
+		fmt.Printf("Changed %q from %q → %q\n", change.Key, change.OldValue, change.NewValue)
+	}
+	fmt.Printf("Inserted %d layers\n", len(diff.Inserted))
+	// Output:
+	// Added "request-id" → "abc123"
+	// Changed "role" from "viewer" → "admin"
+	// Inserted 2 layers
+}
@@ -0,0 +1,119 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+
+	deadline := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		title   string
+		wrapper func() (context.Context, context.Context)
+		diff    func(a, b context.Context) Difference
+	}{
+		{
+			title: "nil contexts",
+			wrapper: func() (context.Context, context.Context) {
+				return nil, nil
+			},
+			diff: func(a, b context.Context) Difference {
+				return Difference{}
+			},
+		},
+		{
+			title: "same context",
+			wrapper: func() (context.Context, context.Context) {
+				ctx := context.WithValue(context.Background(), "key", "value")
+				return ctx, ctx
+			},
+			diff: func(a, b context.Context) Difference {
+				return Difference{Ancestor: a}
+			},
+		},
+		{
+			title: "unrelated contexts",
+			wrapper: func() (context.Context, context.Context) {
+				a := context.WithValue(context.Background(), "key-1", "value-1")
+				b := context.WithValue(&customValueContext{context.TODO()}, "key-2", "value-2")
+				return a, b
+			},
+			diff: func(a, b context.Context) Difference {
+				return Difference{
+					Inserted: []context.Context{Unwrap(Unwrap(b)), Unwrap(b), b},
+					Dropped:  []context.Context{Unwrap(a), a},
+					Added:    []Pair{{"key-2", "value-2"}},
+					Removed:  []Pair{{"key-1", "value-1"}},
+				}
+			},
+		},
+		{
+			title: "added, removed, and changed keys",
+			wrapper: func() (context.Context, context.Context) {
+				root := context.Background()
+				root = context.WithValue(root, "key-1", "value-1")
+				root = context.WithValue(root, "key-2", []string{"value-2"})
+				a := context.WithValue(root, "key-3", "value-3")
+				b := context.WithValue(root, "key-1", "VALUE-ONE")
+				b = context.WithValue(b, "key-2", []string{"value-2"})
+				b = context.WithValue(b, "key-4", "value-4")
+				return a, b
+			},
+			diff: func(a, b context.Context) Difference {
+				return Difference{
+					Ancestor: Unwrap(a),
+					Inserted: []context.Context{Unwrap(Unwrap(b)), Unwrap(b), b},
+					Dropped:  []context.Context{a},
+					Added:    []Pair{{"key-4", "value-4"}},
+					Removed:  []Pair{{"key-3", "value-3"}},
+					Changed:  []Change{{"key-1", "value-1", "VALUE-ONE"}},
+				}
+			},
+		},
+		{
+			title: "deadline and cancel",
+			wrapper: func() (context.Context, context.Context) {
+				a := context.Background()
+				b, cancel := context.WithDeadline(a, deadline)
+				cancel()
+				return a, b
+			},
+			diff: func(a, b context.Context) Difference {
+				return Difference{
+					Ancestor: a,
+					Inserted: []context.Context{b},
+					After: State{
+						Deadline:    deadline,
+						HasDeadline: true,
+						Err:         context.Canceled,
+					},
+				}
+			},
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			a, b := test.wrapper()
+
+			assert.Equal(t, test.diff(a, b), Diff(a, b))
+
+		})
+
+	}
+
+}
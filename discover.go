@@ -0,0 +1,152 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+)
+
+var discoveryEnabled atomic.Bool
+
+// EnableDiscovery enables or disables parent discovery. While enabled, Unwrap
+// falls back to DiscoverParent for contexts that do not hold their parent in
+// a field named "Context", which in turn lets Keys, Pairs, and friends walk
+// through custom context implementations. Contexts with an ambiguous parent
+// are still not unwrapped. Discovery is disabled by default.
+func EnableDiscovery(enabled bool) {
+	discoveryEnabled.Store(enabled)
+}
+
+// AmbiguousParentError is returned by DiscoverParent when a context holds more
+// than one distinct context, and so its parent can not be determined.
+type AmbiguousParentError struct {
+	// Type is the type of the context that was inspected.
+	Type reflect.Type
+
+	// Fields contains the path of every field that holds a context, such as
+	// "parent" or "inner.base".
+	Fields []string
+}
+
+func (e *AmbiguousParentError) Error() string {
+	return fmt.Sprintf("ambiguous parent context for %s, found in fields %s", e.Type, strings.Join(e.Fields, ", "))
+}
+
+// maxDiscoveryDepth limits how deeply DiscoverParent will look inside of
+// nested structs and pointers.
+const maxDiscoveryDepth = 4
+
+// DiscoverParent takes a context and returns the parent context that it holds,
+// by scanning every field, including unexported fields and fields of nested
+// structs, for a value that implements context.Context. If no such field is
+// found, nil is returned. If fields holding more than one distinct context are
+// found, an *AmbiguousParentError is returned. A passed nil context will
+// return nil.
+func DiscoverParent(ctx context.Context) (context.Context, error) {
+
+	// Guard against contexts that intentionally hide their parent
+	if _, ok := ctx.(parentHider); ok {
+		return nil, nil
+	}
+
+	// Obtain the struct underlying the context
+	contextVal, ok := layerStruct(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	d := discoverer{self: ctx}
+	d.scan(contextVal, "", 0)
+
+	switch len(d.parents) {
+	case 0:
+		return nil, nil
+	case 1:
+		return d.parents[0], nil
+	default:
+		return nil, &AmbiguousParentError{
+			Type:   reflect.TypeOf(ctx),
+			Fields: d.fields,
+		}
+	}
+}
+
+// parentHider is implemented by contexts from this package that intentionally
+// hide their parent from inspection.
+type parentHider interface {
+	hidesParent()
+}
+
+// discoverer collects every distinct context held by the fields of a struct.
+type discoverer struct {
+	self    context.Context
+	parents []context.Context
+	fields  []string
+}
+
+func (d *discoverer) scan(structVal reflect.Value, prefix string, depth int) {
+	for index := 0; index < structVal.NumField(); index++ {
+		field := structVal.Field(index)
+		path := prefix + structVal.Type().Field(index).Name
+
+		switch field.Kind() {
+		case reflect.Interface, reflect.Pointer:
+			if field.IsNil() {
+				continue
+			}
+
+			// Does this field hold a context?
+			if parent, ok := readField(field).(context.Context); ok {
+				d.add(parent, path)
+				continue
+			}
+
+			// Otherwise look inside of the struct that it points to
+			if depth < maxDiscoveryDepth {
+				if nested, ok := layerStruct(readField(field)); ok {
+					d.scan(nested, path+".", depth+1)
+				}
+			}
+
+		case reflect.Struct:
+			// Does this field hold a context by value? Embedded structs are
+			// skipped, as they are part of the context itself (such as the
+			// emptyCtx inside of context.Background)
+			if !structVal.Type().Field(index).Anonymous {
+				if parent, ok := readField(field).(context.Context); ok {
+					d.add(parent, path)
+					continue
+				}
+			}
+
+			if depth < maxDiscoveryDepth {
+				d.scan(field, path+".", depth+1)
+			}
+		}
+	}
+}
+
+// add records a context found in the field with the given path.
+func (d *discoverer) add(parent context.Context, path string) {
+
+	// Guard against contexts that refer to themselves
+	if same(parent, d.self) {
+		return
+	}
+
+	d.fields = append(d.fields, path)
+
+	for _, existing := range d.parents {
+		if same(existing, parent) {
+			return
+		}
+	}
+
+	d.parents = append(d.parents, parent)
+}
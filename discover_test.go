@@ -0,0 +1,194 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// parentFieldContext stores its parent in an unexported field.
+type parentFieldContext struct {
+	interfaceFieldContext
+	parent context.Context
+}
+
+// nestedFieldContext stores its parent deep inside of a nested struct.
+type nestedFieldContext struct {
+	interfaceFieldContext
+	state *nestedState
+}
+
+type nestedState struct {
+	name  string
+	inner struct {
+		base context.Context
+	}
+}
+
+// valueFieldContext stores its parent by value.
+type valueFieldContext struct {
+	interfaceFieldContext
+	base stringerContext
+}
+
+// ambiguousContext stores two distinct contexts.
+type ambiguousContext struct {
+	interfaceFieldContext
+	base  context.Context
+	inner context.Context
+}
+
+func TestDiscoverParent(t *testing.T) {
+
+	root := context.WithValue(context.Background(), "key", "value")
+
+	tests := []struct {
+		title  string
+		ctx    context.Context
+		parent context.Context
+		err    string
+	}{
+		{
+			title: "nil context",
+			ctx:   nil,
+		},
+		{
+			title: "background context",
+			ctx:   context.Background(),
+		},
+		{
+			title:  "value context",
+			ctx:    context.WithValue(root, "key", "value"),
+			parent: root,
+		},
+		{
+			title:  "without cancel context",
+			ctx:    context.WithoutCancel(root),
+			parent: root,
+		},
+		{
+			title:  "parent field context",
+			ctx:    &parentFieldContext{parent: root},
+			parent: root,
+		},
+		{
+			title: "nested field context",
+			ctx: func() context.Context {
+				state := &nestedState{name: "nested"}
+				state.inner.base = root
+				return &nestedFieldContext{state: state}
+			}(),
+			parent: root,
+		},
+		{
+			title:  "value field context",
+			ctx:    &valueFieldContext{base: stringerContext{root}},
+			parent: stringerContext{root},
+		},
+		{
+			title: "nil nested field context",
+			ctx:   &nestedFieldContext{},
+		},
+		{
+			title:  "duplicate field context",
+			ctx:    &ambiguousContext{base: root, inner: root},
+			parent: root,
+		},
+		{
+			title: "ambiguous context",
+			ctx:   &ambiguousContext{base: root, inner: context.Background()},
+			err:   "ambiguous parent context for *contents.ambiguousContext, found in fields base, inner",
+		},
+		{
+			title: "self referencing context",
+			ctx: func() context.Context {
+				ctx := &parentFieldContext{}
+				ctx.parent = ctx
+				return ctx
+			}(),
+		},
+		{
+			title: "without context",
+			ctx:   Without(root, "key"),
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			parent, err := DiscoverParent(test.ctx)
+
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				assert.IsType(t, &AmbiguousParentError{}, err)
+				assert.Equal(t, reflect.TypeOf(test.ctx), err.(*AmbiguousParentError).Type)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, test.parent, parent)
+
+		})
+
+	}
+
+}
+
+func TestEnableDiscovery(t *testing.T) {
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "key-1", "value-1")
+	ctx = &parentFieldContext{parent: ctx}
+	ctx = context.WithValue(ctx, "key-2", "value-2")
+	ctx = context.WithoutCancel(ctx)
+	ctx = context.WithValue(ctx, "key-3", "value-3")
+
+	assert.Equal(t, []interface{}{"key-3"}, Keys(ctx))
+
+	EnableDiscovery(true)
+	defer EnableDiscovery(false)
+
+	assert.Equal(t, []interface{}{"key-1", "key-2", "key-3"}, Keys(ctx))
+	assert.Equal(t, []interface{}{"key-3"}, Keys(Without(ctx, "key-1", "key-2")))
+
+}
+
+func TestDiscoverCycle(t *testing.T) {
+
+	first := &parentFieldContext{}
+	second := &parentFieldContext{parent: first}
+	first.parent = second
+
+	EnableDiscovery(true)
+	defer EnableDiscovery(false)
+
+	parent, err := DiscoverParent(first)
+	assert.Nil(t, err)
+	assert.True(t, parent == context.Context(second))
+
+	assert.Equal(t, 1, Depth(first))
+	assert.Equal(t, 1, Depth(second))
+	assert.Empty(t, Keys(first))
+	assert.Empty(t, Pairs(first))
+	assert.True(t, IsAncestor(second, first))
+	assert.True(t, IsAncestor(first, second))
+
+	assert.NotPanics(t, func() {
+		Snapshot(first)
+		Probe(first, "key")
+		Diff(first, second)
+		Size(first)
+		Fingerprint(first)
+	})
+
+}
@@ -0,0 +1,44 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+)
+
+// Filter returns a context that contains only the key:value pairs from the
+// given context for which keep returns true, in the order in which they were
+// originally added. The deadline and cancellation of the given context are
+// preserved. A passed nil context will return nil.
+func Filter(ctx context.Context, keep func(Pair) bool) context.Context {
+	if ctx == nil {
+		return nil
+	}
+
+	var pairs []Pair
+
+	for _, pair := range Pairs(ctx) {
+		if keep(pair) {
+			pairs = append(pairs, pair)
+		}
+	}
+
+	return rebuild(&cancelOnlyCtx{ctx}, pairs)
+}
+
+// Without returns a context that contains every key:value pair from the given
+// context, except for those whose key is one of the given keys. The deadline
+// and cancellation of the given context are preserved. A passed nil context
+// will return nil.
+func Without(ctx context.Context, keys ...interface{}) context.Context {
+	return Filter(ctx, func(pair Pair) bool {
+		for _, key := range keys {
+			if identical(pair.Key, key) {
+				return false
+			}
+		}
+		return true
+	})
+}
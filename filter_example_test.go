@@ -0,0 +1,26 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshdk/contents"
+)
+
+func ExampleWithout() {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "user", "alice")
+	ctx = context.WithValue(ctx, "token", "s3cr3t")
+
+	ctx = contents.Without(ctx, "token")
+
+	fmt.Printf("Value of user is %q\n", ctx.Value("user"))
+	fmt.Printf("Value of token is %v\n", ctx.Value("token"))
+	// Output:
+	// Value of user is "alice"
+	// Value of token is <nil>
+}
@@ -0,0 +1,162 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithout(t *testing.T) {
+
+	tests := []struct {
+		title string
+		ctx   context.Context
+		keys  []interface{}
+		pairs []Pair
+		err   error
+	}{
+		{
+			title: "nil context",
+			ctx:   nil,
+			keys:  []interface{}{"key"},
+		},
+		{
+			title: "background context",
+			ctx:   context.Background(),
+			keys:  []interface{}{"key"},
+		},
+		{
+			title: "no keys",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				return ctx
+			}(),
+			pairs: []Pair{
+				{"key-1", "value-1"},
+				{"key-2", "value-2"},
+			},
+		},
+		{
+			title: "missing key",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				return ctx
+			}(),
+			keys: []interface{}{"key-2", 1, []string{"key-1"}},
+			pairs: []Pair{
+				{"key-1", "value-1"},
+			},
+		},
+		{
+			title: "uncomparable key",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = &structValueContext{ctx, []int{1}, nil}
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				return ctx
+			}(),
+			keys: []interface{}{[]int{1}},
+			pairs: []Pair{
+				{"key-1", "value-1"},
+				{"key-2", "value-2"},
+			},
+		},
+		{
+			title: "single key",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				ctx = context.WithValue(ctx, "key-3", "value-3")
+				return ctx
+			}(),
+			keys: []interface{}{"key-2"},
+			pairs: []Pair{
+				{"key-1", "value-1"},
+				{"key-3", "value-3"},
+			},
+		},
+		{
+			title: "duplicate key",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				ctx = context.WithValue(ctx, "key-1", "VALUE-ONE")
+				return ctx
+			}(),
+			keys: []interface{}{"key-1"},
+			pairs: []Pair{
+				{"key-2", "value-2"},
+			},
+		},
+		{
+			title: "canceled context",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				return ctx
+			}(),
+			keys: []interface{}{"key-1"},
+			pairs: []Pair{
+				{"key-2", "value-2"},
+			},
+			err: context.Canceled,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			ctx := Without(test.ctx, test.keys...)
+
+			if test.ctx == nil {
+				assert.Nil(t, ctx)
+				return
+			}
+
+			assert.Equal(t, test.pairs, Pairs(ctx))
+			assert.Equal(t, test.err, ctx.Err())
+
+			for _, key := range test.keys {
+				assert.Nil(t, ctx.Value(key))
+			}
+
+		})
+
+	}
+
+}
+
+func TestFilter(t *testing.T) {
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "key-1", 1)
+	ctx = context.WithValue(ctx, "key-2", "value-2")
+	ctx = context.WithValue(ctx, "key-3", 3)
+
+	filtered := Filter(ctx, func(pair Pair) bool {
+		_, ok := pair.Value.(int)
+		return ok
+	})
+
+	assert.Equal(t, []Pair{{"key-1", 1}, {"key-3", 3}}, Pairs(filtered))
+	assert.Nil(t, filtered.Value("key-2"))
+
+}
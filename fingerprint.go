@@ -0,0 +1,236 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"sync"
+)
+
+// Fingerprinter can be implemented by values that know how to fingerprint
+// themselves. Values with equal fingerprints are considered to be equal.
+type Fingerprinter interface {
+	Fingerprint() uint64
+}
+
+var (
+	hashersMu sync.RWMutex
+	hashers   = map[reflect.Type]func(interface{}) uint64{}
+)
+
+// RegisterHasher registers a function for fingerprinting values of type T,
+// which is used in place of the default reflection based hashing. This is
+// useful for types that can not be changed to implement Fingerprinter.
+func RegisterHasher[T any](fn func(T) uint64) {
+	hashersMu.Lock()
+	defer hashersMu.Unlock()
+
+	hashers[reflect.TypeFor[T]()] = func(value interface{}) uint64 {
+		return fn(value.(T))
+	}
+}
+
+// lookupHasher returns the hasher registered for the given type, and if such a
+// hasher exists.
+func lookupHasher(typ reflect.Type) (func(interface{}) uint64, bool) {
+	hashersMu.RLock()
+	defer hashersMu.RUnlock()
+
+	fn, found := hashers[typ]
+	return fn, found
+}
+
+// Fingerprint will return a hash of every key:value pair returned by Map.
+// Contexts that resolve the same keys to the same values will have the same
+// fingerprint, regardless of the order in which values were added, or of the
+// layers in between them. A passed nil context will return the fingerprint of
+// an empty context.
+//
+// Values are hashed using, in order of preference, a function registered with
+// RegisterHasher, the Fingerprinter interface, or reflection. Reflection
+// hashes pointers, channels, and functions by their address, and everything
+// else, including slices and maps, by their contents. Fingerprints of values
+// that contain addresses are only stable within a single process.
+func Fingerprint(ctx context.Context) uint64 {
+	var sum uint64
+
+	for key, value := range Map(ctx) {
+		h := fingerprinter{
+			hash: fnv.New64a(),
+			path: map[objectKey]bool{},
+		}
+
+		h.value(reflect.ValueOf(&key).Elem())
+		h.value(reflect.ValueOf(&value).Elem())
+
+		// Combine entries in a way that does not depend on their order
+		sum += mix(h.hash.Sum64())
+	}
+
+	return mix(sum)
+}
+
+// fingerprinter writes a canonical encoding of values to a hash.
+type fingerprinter struct {
+	hash hash.Hash64
+
+	// path contains the slices and maps currently being hashed, in order to
+	// guard against values that contain themselves
+	path map[objectKey]bool
+}
+
+func (f *fingerprinter) uint(value uint64) {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], value)
+	f.hash.Write(buf[:])
+}
+
+func (f *fingerprinter) string(value string) {
+	f.uint(uint64(len(value)))
+	f.hash.Write([]byte(value))
+}
+
+func (f *fingerprinter) float(value float64) {
+	// Negative zero is equal to zero, and so must hash the same
+	if value == 0 {
+		value = 0
+	}
+	f.uint(math.Float64bits(value))
+}
+
+func (f *fingerprinter) value(v reflect.Value) {
+	if !v.IsValid() {
+		f.string("<nil>")
+		return
+	}
+
+	f.string(v.Type().String())
+
+	// Prefer a registered hasher, followed by the value's own method
+	if v.CanInterface() {
+		if fn, found := lookupHasher(v.Type()); found {
+			f.uint(fn(v.Interface()))
+			return
+		}
+		if fp, ok := v.Interface().(Fingerprinter); ok && v.Kind() != reflect.Interface {
+			f.uint(fp.Fingerprint())
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			f.uint(1)
+		} else {
+			f.uint(0)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.uint(uint64(v.Int()))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f.uint(v.Uint())
+
+	case reflect.Float32, reflect.Float64:
+		f.float(v.Float())
+
+	case reflect.Complex64, reflect.Complex128:
+		f.float(real(v.Complex()))
+		f.float(imag(v.Complex()))
+
+	case reflect.String:
+		f.string(v.String())
+
+	case reflect.Pointer, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		f.uint(uint64(v.Pointer()))
+
+	case reflect.Interface:
+		if v.IsNil() {
+			f.string("<nil>")
+			return
+		}
+		f.value(v.Elem())
+
+	case reflect.Array:
+		for index := 0; index < v.Len(); index++ {
+			f.value(v.Index(index))
+		}
+
+	case reflect.Struct:
+		for index := 0; index < v.NumField(); index++ {
+			f.value(v.Field(index))
+		}
+
+	case reflect.Slice:
+		if !f.enter(v) {
+			return
+		}
+		defer f.leave(v)
+
+		f.uint(uint64(v.Len()))
+		for index := 0; index < v.Len(); index++ {
+			f.value(v.Index(index))
+		}
+
+	case reflect.Map:
+		if !f.enter(v) {
+			return
+		}
+		defer f.leave(v)
+
+		// Hash every entry separately, and combine them in a way that does
+		// not depend on the order of iteration
+		var sum uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := fingerprinter{hash: fnv.New64a(), path: f.path}
+			entry.value(iter.Key())
+			entry.value(iter.Value())
+			sum += mix(entry.hash.Sum64())
+		}
+
+		f.uint(uint64(v.Len()))
+		f.uint(sum)
+	}
+}
+
+// enter marks the given slice or map as being hashed, and reports if it was
+// not already being hashed.
+func (f *fingerprinter) enter(v reflect.Value) bool {
+	if v.IsNil() {
+		f.string("<nil>")
+		return false
+	}
+
+	key := objectKey{v.Pointer(), v.Type()}
+	if f.path[key] {
+		f.string("<cycle>")
+		return false
+	}
+	f.path[key] = true
+	return true
+}
+
+// leave marks the given slice or map as no longer being hashed.
+func (f *fingerprinter) leave(v reflect.Value) {
+	delete(f.path, objectKey{v.Pointer(), v.Type()})
+}
+
+// mix scrambles the bits of the given value, so that summing mixed values
+// does not cancel out structure in the original values.
+func mix(value uint64) uint64 {
+	value ^= value >> 33
+	value *= 0xff51afd7ed558ccd
+	value ^= value >> 33
+	value *= 0xc4ceb9fe1a85ec53
+	value ^= value >> 33
+	return value
+}
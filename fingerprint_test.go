@@ -0,0 +1,196 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fingerprintedValue struct {
+	id      int
+	ignored []string
+}
+
+func (v fingerprintedValue) Fingerprint() uint64 {
+	return uint64(v.id)
+}
+
+type hashedValue struct {
+	id      int
+	ignored []string
+}
+
+func TestFingerprint(t *testing.T) {
+
+	RegisterHasher(func(v hashedValue) uint64 {
+		return uint64(v.id)
+	})
+
+	pointer := &struct{}{}
+	recursive := []interface{}{nil}
+	recursive[0] = recursive
+
+	tests := []struct {
+		title string
+		a     context.Context
+		b     context.Context
+		equal bool
+	}{
+		{
+			title: "nil and background contexts",
+			a:     nil,
+			b:     context.Background(),
+			equal: true,
+		},
+		{
+			title: "empty and non-empty contexts",
+			a:     context.Background(),
+			b:     context.WithValue(context.Background(), "key", "value"),
+		},
+		{
+			title: "different insertion order",
+			a: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				return ctx
+			}(),
+			b: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				ctx, cancel := context.WithCancel(ctx)
+				_ = cancel
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				return ctx
+			}(),
+			equal: true,
+		},
+		{
+			title: "shadowed values",
+			a: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key", "old")
+				ctx = context.WithValue(ctx, "key", "new")
+				return ctx
+			}(),
+			b:     context.WithValue(context.Background(), "key", "new"),
+			equal: true,
+		},
+		{
+			title: "different values",
+			a:     context.WithValue(context.Background(), "key", "value-1"),
+			b:     context.WithValue(context.Background(), "key", "value-2"),
+		},
+		{
+			title: "swapped keys and values",
+			a: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "a", "b")
+				ctx = context.WithValue(ctx, "b", "a")
+				return ctx
+			}(),
+			b: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "a", "a")
+				ctx = context.WithValue(ctx, "b", "b")
+				return ctx
+			}(),
+		},
+		{
+			title: "different value types",
+			a:     context.WithValue(context.Background(), "key", 1),
+			b:     context.WithValue(context.Background(), "key", uint(1)),
+		},
+		{
+			title: "equal slices and maps",
+			a: context.WithValue(context.Background(), "key", map[string][]int{
+				"a": {1, 2},
+				"b": {3},
+			}),
+			b: context.WithValue(context.Background(), "key", map[string][]int{
+				"b": {3},
+				"a": {1, 2},
+			}),
+			equal: true,
+		},
+		{
+			title: "different slices",
+			a:     context.WithValue(context.Background(), "key", []int{1, 2}),
+			b:     context.WithValue(context.Background(), "key", []int{2, 1}),
+		},
+		{
+			title: "nil and empty slices",
+			a:     context.WithValue(context.Background(), "key", []int(nil)),
+			b:     context.WithValue(context.Background(), "key", []int{}),
+		},
+		{
+			title: "same pointers",
+			a:     context.WithValue(context.Background(), "key", pointer),
+			b:     context.WithValue(context.Background(), "key", pointer),
+			equal: true,
+		},
+		{
+			title: "different pointers",
+			a:     context.WithValue(context.Background(), "key", &struct{ int }{}),
+			b:     context.WithValue(context.Background(), "key", &struct{ int }{}),
+		},
+		{
+			title: "negative zero",
+			a:     context.WithValue(context.Background(), "key", 0.0),
+			b:     context.WithValue(context.Background(), "key", math.Copysign(0, -1)),
+			equal: true,
+		},
+		{
+			title: "fingerprinter values",
+			a:     context.WithValue(context.Background(), "key", fingerprintedValue{1, []string{"a"}}),
+			b:     context.WithValue(context.Background(), "key", fingerprintedValue{1, []string{"b"}}),
+			equal: true,
+		},
+		{
+			title: "registered hasher values",
+			a:     context.WithValue(context.Background(), "key", hashedValue{1, []string{"a"}}),
+			b:     context.WithValue(context.Background(), "key", hashedValue{1, []string{"b"}}),
+			equal: true,
+		},
+		{
+			title: "different registered hasher values",
+			a:     context.WithValue(context.Background(), "key", hashedValue{1, nil}),
+			b:     context.WithValue(context.Background(), "key", hashedValue{2, nil}),
+		},
+		{
+			title: "recursive values",
+			a:     context.WithValue(context.Background(), "key", recursive),
+			b:     context.WithValue(context.Background(), "key", recursive),
+			equal: true,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			a := Fingerprint(test.a)
+			b := Fingerprint(test.b)
+
+			assert.Equal(t, a, Fingerprint(test.a))
+
+			if test.equal {
+				assert.Equal(t, a, b)
+			} else {
+				assert.NotEqual(t, a, b)
+			}
+
+		})
+
+	}
+
+}
@@ -0,0 +1,41 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+)
+
+// Flatten returns a context that wraps the given context, and answers
+// ".Value(key)" from a precomputed map of every key:value pair returned by
+// Map. Keys that are not held in the map are looked up from the given context.
+// A passed nil context will return nil.
+//
+// The returned context can be unwrapped, so Keys, Pairs, and friends will
+// continue to report the contents of the given context.
+func Flatten(ctx context.Context) context.Context {
+	if ctx == nil {
+		return nil
+	}
+
+	return &flatCtx{
+		Context: ctx,
+		values:  Map(ctx),
+	}
+}
+
+// flatCtx is a context that answers ".Value(key)" from a map, before falling
+// back to the wrapped context.
+type flatCtx struct {
+	context.Context
+	values map[interface{}]interface{}
+}
+
+func (c *flatCtx) Value(key interface{}) interface{} {
+	if value, found := c.values[key]; found {
+		return value
+	}
+	return c.Context.Value(key)
+}
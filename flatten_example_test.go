@@ -0,0 +1,27 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshdk/contents"
+)
+
+func ExampleFlatten() {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "key a", "value a")
+	ctx = context.WithValue(ctx, "key b", "value b")
+	ctx = context.WithValue(ctx, "key a", "VALUE A")
+
+	flat := contents.Flatten(ctx)
+
+	fmt.Printf("Value of key a is %q\n", flat.Value("key a"))
+	fmt.Printf("Number of keys is %d\n", len(contents.Keys(flat)))
+	// Output:
+	// Value of key a is "VALUE A"
+	// Number of keys is 3
+}
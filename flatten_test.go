@@ -0,0 +1,100 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+
+	tests := []struct {
+		title string
+		ctx   context.Context
+		err   error
+	}{
+		{
+			title: "nil context",
+			ctx:   nil,
+		},
+		{
+			title: "background context",
+			ctx:   context.Background(),
+		},
+		{
+			title: "multi key context",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				ctx = context.WithValue(ctx, "key-3", "value-3")
+				return ctx
+			}(),
+		},
+		{
+			title: "duplicate key context",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				ctx = context.WithValue(ctx, "key-1", nil)
+				return ctx
+			}(),
+		},
+		{
+			title: "canceled context with keys",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				return ctx
+			}(),
+			err: context.Canceled,
+		},
+		{
+			title: "custom value context",
+			ctx: func() context.Context {
+				var ctx context.Context = &customValueContext{context.Background()}
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				return ctx
+			}(),
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			ctx := Flatten(test.ctx)
+
+			if test.ctx == nil {
+				assert.Nil(t, ctx)
+				return
+			}
+
+			assert.Equal(t, test.ctx, Unwrap(ctx))
+			assert.Equal(t, Pairs(test.ctx), Pairs(ctx))
+			assert.Equal(t, test.err, ctx.Err())
+
+			for _, key := range append(Keys(test.ctx), "custom", "missing") {
+				assert.Equal(t, test.ctx.Value(key), ctx.Value(key))
+			}
+
+			_, found := Key(ctx)
+			assert.False(t, found)
+
+		})
+
+	}
+
+}
@@ -6,6 +6,7 @@ package contents
 
 import (
 	"context"
+	"reflect"
 )
 
 type Pair struct {
@@ -19,16 +20,12 @@ type Pair struct {
 func Keys(ctx context.Context) []interface{} {
 	var keys []interface{}
 
-	// Do we have a parent context?
-	parent := Unwrap(ctx)
-	if parent != nil {
-		// Extract keys from parent first
-		keys = Keys(parent)
-	}
-
-	// Do we have a key?
-	if key, found := Key(ctx); found {
-		keys = append(keys, key)
+	// Extract keys from the innermost layer first
+	layers := chain(ctx)
+	for index := len(layers) - 1; index >= 0; index-- {
+		if key, found := Key(layers[index]); found {
+			keys = append(keys, key)
+		}
 	}
 
 	return keys
@@ -41,19 +38,17 @@ func Keys(ctx context.Context) []interface{} {
 func Pairs(ctx context.Context) []Pair {
 	var pairs []Pair
 
-	// Do we have a parent context?
-	parent := Unwrap(ctx)
-	if parent != nil {
-		// Extract pairs from parent first
-		pairs = Pairs(parent)
-	}
+	// Extract pairs from the innermost layer first
+	layers := chain(ctx)
+	for index := len(layers) - 1; index >= 0; index-- {
 
-	// Do we have a key, and by extension, a value?
-	if key, found := Key(ctx); found {
-		pairs = append(pairs, Pair{
-			Key:   key,
-			Value: ctx.Value(key),
-		})
+		// Do we have a key, and by extension, a value?
+		if key, found := Key(layers[index]); found {
+			pairs = append(pairs, Pair{
+				Key:   key,
+				Value: layers[index].Value(key),
+			})
+		}
 	}
 
 	return pairs
@@ -61,10 +56,17 @@ func Pairs(ctx context.Context) []Pair {
 
 // Map will return every key:value pair contained withing the context. The
 // mapped value is the result of calling ".Value(key)" on the given context.
+// Keys that can not be used in a map, such as those reported for some custom
+// contexts, are skipped.
 func Map(ctx context.Context) map[interface{}]interface{} {
 	pairs := map[interface{}]interface{}{}
 
 	for _, key := range Keys(ctx) {
+		// Guard against keys that can not be used in a map
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			continue
+		}
+
 		pairs[key] = ctx.Value(key)
 	}
 
@@ -0,0 +1,139 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+)
+
+// HeaderPrefix is prepended to the name of a registered codec to form the
+// name of the HTTP header that carries its value.
+const HeaderPrefix = "Contents-"
+
+// Inject sets a header for the value of every key within the context that has
+// a registered codec. Encoded values are base64 encoded, so that arbitrary
+// bytes can be carried safely. Shadowed values are not included.
+func Inject(ctx context.Context, header http.Header) error {
+	for _, pair := range live(Pairs(ctx)) {
+		registered, found := lookupCodec(pair.Key)
+		if !found {
+			continue
+		}
+
+		data, err := registered.codec.Encode(pair.Value)
+		if err != nil {
+			return fmt.Errorf("encoding %q: %w", registered.name, err)
+		}
+
+		header.Set(HeaderPrefix+registered.name, base64.RawURLEncoding.EncodeToString(data))
+	}
+
+	return nil
+}
+
+// Extract will return a context derived from the given context that contains
+// a value for every registered codec whose header is present. Values are added
+// in order of their codec name. A passed nil context is treated as
+// context.Background().
+func Extract(ctx context.Context, header http.Header) (context.Context, error) {
+	return extract(ctx, header, nil)
+}
+
+// extract behaves like Extract, but only considers codecs whose name is in the
+// given set. A nil set considers every registered codec.
+func extract(ctx context.Context, header http.Header, accepted map[string]bool) (context.Context, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var pairs []Pair
+
+	for _, registered := range registeredCodecs() {
+		if accepted != nil && !accepted[registered.name] {
+			continue
+		}
+
+		encoded := header.Get(HeaderPrefix + registered.name)
+		if encoded == "" {
+			continue
+		}
+
+		data, err := base64.RawURLEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %q: %w", registered.name, err)
+		}
+
+		value, err := registered.codec.Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %q: %w", registered.name, err)
+		}
+
+		pairs = append(pairs, Pair{
+			Key:   registered.key,
+			Value: value,
+		})
+	}
+
+	return rebuild(ctx, pairs), nil
+}
+
+// Handler returns middleware that extracts registered context values from the
+// headers of each request, and adds them to the request context. Requests with
+// malformed headers are rejected with a 400 Bad Request.
+//
+// Only values for the codecs with the given names are extracted, or for every
+// registered codec if no names are given. Headers are trusted as is, so any
+// caller that can reach the handler can set these values. Handler should only
+// be used behind trusted internal hops, with headers from outside callers
+// removed, and should be given only the names of codecs that are safe to
+// accept from those hops.
+func Handler(next http.Handler, names ...string) http.Handler {
+	var accepted map[string]bool
+	if len(names) > 0 {
+		accepted = make(map[string]bool, len(names))
+		for _, name := range names {
+			accepted[name] = true
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, err := extract(r.Context(), r.Header, accepted)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Transport is an http.RoundTripper that injects registered context values
+// from the context of each request into its headers.
+type Transport struct {
+	// Base is the http.RoundTripper used to make requests. If nil,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper. The given request is not modified.
+func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	clone := r.Clone(r.Context())
+	if err := Inject(r.Context(), clone.Header); err != nil {
+		if r.Body != nil {
+			r.Body.Close()
+		}
+		return nil, err
+	}
+
+	return base.RoundTrip(clone)
+}
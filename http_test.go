@@ -0,0 +1,187 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectExtract(t *testing.T) {
+
+	RegisterCodec(codecKey("http-tenant"), "http-tenant", JSONCodec[string]())
+	RegisterCodec(codecKey("http-retries"), "http-retries", JSONCodec[int]())
+	RegisterCodec(codecKey("http-broken"), "http-broken", Codec{
+		Encode: func(value interface{}) ([]byte, error) {
+			return nil, errors.New("broken")
+		},
+	})
+
+	tests := []struct {
+		title  string
+		ctx    context.Context
+		header http.Header
+		pairs  []Pair
+		err    string
+	}{
+		{
+			title:  "nil context",
+			ctx:    nil,
+			header: http.Header{},
+		},
+		{
+			title: "registered keys",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, codecKey("http-tenant"), "acme")
+				ctx = context.WithValue(ctx, "other", "value")
+				ctx = context.WithValue(ctx, codecKey("http-retries"), 3)
+				return ctx
+			}(),
+			header: http.Header{
+				"Contents-Http-Retries": []string{"Mw"},
+				"Contents-Http-Tenant":  []string{"ImFjbWUi"},
+			},
+			pairs: []Pair{
+				{codecKey("http-retries"), 3},
+				{codecKey("http-tenant"), "acme"},
+			},
+		},
+		{
+			title: "encoding error",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, codecKey("http-broken"), "value")
+				return ctx
+			}(),
+			err: `encoding "http-broken": broken`,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			header := http.Header{}
+
+			err := Inject(test.ctx, header)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, test.header, header)
+
+			ctx, err := Extract(nil, header)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.pairs, Pairs(ctx))
+
+		})
+
+	}
+
+}
+
+func TestExtractErrors(t *testing.T) {
+
+	RegisterCodec(codecKey("http-count"), "http-count", JSONCodec[int]())
+
+	_, err := Extract(context.Background(), http.Header{
+		"Contents-Http-Count": []string{"!!!"},
+	})
+	assert.EqualError(t, err, `decoding "http-count": illegal base64 data at input byte 0`)
+
+	_, err = Extract(context.Background(), http.Header{
+		"Contents-Http-Count": []string{"Im9uZSI"},
+	})
+	assert.EqualError(t, err, `decoding "http-count": json: cannot unmarshal string into Go value of type int`)
+
+}
+
+func TestTransportHandler(t *testing.T) {
+
+	RegisterCodec(codecKey("http-request-id"), "http-request-id", JSONCodec[string]())
+
+	server := httptest.NewServer(Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Context().Value(codecKey("http-request-id")))
+	})))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{}}
+
+	ctx := context.WithValue(context.Background(), codecKey("http-request-id"), "abc123")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "abc123", string(body[:n]))
+	assert.Empty(t, req.Header)
+
+}
+
+func TestHandlerMalformed(t *testing.T) {
+
+	RegisterCodec(codecKey("http-malformed"), "http-malformed", JSONCodec[int]())
+
+	handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not have been called")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Contents-Http-Malformed", "!!!")
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+}
+
+func TestHandlerAccepted(t *testing.T) {
+
+	RegisterCodec(codecKey("http-tenant"), "http-tenant", JSONCodec[string]())
+	RegisterCodec(codecKey("http-admin"), "http-admin", JSONCodec[bool]())
+	RegisterCodec(codecKey("http-ignored"), "http-ignored", JSONCodec[int]())
+
+	var ctx context.Context
+	handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx = r.Context()
+	}), "http-tenant", "http-ignored")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, Inject(context.WithValue(context.Background(), codecKey("http-tenant"), "acme"), req.Header))
+	require.NoError(t, Inject(context.WithValue(context.Background(), codecKey("http-admin"), true), req.Header))
+
+	// Malformed headers for codecs that are not accepted are ignored
+	req.Header.Set("Contents-Http-Admin", "!!!")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	require.NotNil(t, ctx)
+	assert.Equal(t, "acme", ctx.Value(codecKey("http-tenant")))
+	assert.Nil(t, ctx.Value(codecKey("http-admin")))
+	assert.Nil(t, ctx.Value(codecKey("http-ignored")))
+
+}
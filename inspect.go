@@ -41,29 +41,70 @@ import (
 //
 // Contexts created with "context.Background()" and "context.TODO()" can not be
 // unwrapped, as they are not derived from a "parent" context.
+//
+// Custom contexts can be unwrapped if they hold their parent in a field named
+// "Context", whether they are implemented by a pointer or by a struct value.
+// Contexts of any other shape are only unwrapped while EnableDiscovery is
+// enabled, and never cause a panic.
 func Unwrap(ctx context.Context) context.Context {
 
-	// Guard against nil contexts
-	if ctx == nil {
+	// Obtain the struct underlying the context
+	contextVal, ok := layerStruct(ctx)
+	if !ok {
 		return nil
 	}
 
-	contextVal := reflect.ValueOf(ctx).Elem()
+	// Obtain the struct field "Context", and check to see if it is actually
+	// a context
+	var parentContext context.Context
+	if contextField, ok := fieldByName(contextVal, "Context"); ok {
+		parentContext, _ = readField(contextField).(context.Context)
+	}
 
-	// Guard against types with no fields (such as context.Background)
-	if contextVal.Kind() != reflect.Struct {
+	// Fall back to scanning every field, if enabled
+	if parentContext == nil && discoveryEnabled.Load() {
+		parentContext, _ = DiscoverParent(ctx)
+	}
+
+	if parentContext == nil {
 		return nil
 	}
 
-	// Obtain the struct field "Context"
-	contextField := contextVal.FieldByName("Context")
+	// Guard against contexts that wrap themselves
+	if same(parentContext, ctx) {
+		return nil
+	}
+
+	return parentContext
+}
 
-	// Check to see if our field is actually a context
-	if parentContext, ok := contextField.Interface().(context.Context); ok {
-		return parentContext
+// chain returns every layer of the given context, from the outermost to the
+// innermost, by repeatedly unwrapping it. A passed nil context will return
+// nil.
+//
+// Contexts that refer to each other in a cycle are only walked until a layer
+// is reached for a second time, so that walks over any context terminate.
+func chain(ctx context.Context) []context.Context {
+	var (
+		layers []context.Context
+		seen   = map[objectKey]bool{}
+	)
+
+	for layer := ctx; layer != nil; layer = Unwrap(layer) {
+
+		// Only layers implemented by a pointer can take part in a cycle
+		if ptr := layerPointer(layer); ptr != nil {
+			key := objectKey{uintptr(unsafe.Pointer(ptr)), reflect.TypeOf(layer)}
+			if seen[key] {
+				break
+			}
+			seen[key] = true
+		}
+
+		layers = append(layers, layer)
 	}
 
-	return nil
+	return layers
 }
 
 // Key takes a context and returns the associated key and if a key exists.
@@ -73,27 +114,119 @@ func Unwrap(ctx context.Context) context.Context {
 // created via other methods will not.
 func Key(ctx context.Context) (interface{}, bool) {
 
-	// Guard against nil contexts
-	if ctx == nil {
+	// Obtain the struct underlying the context
+	contextVal, ok := layerStruct(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	// Obtain the struct field "key"
+	valueKey, ok := fieldByName(contextVal, "key")
+	if !ok {
 		return nil, false
 	}
 
-	contextVal := reflect.ValueOf(ctx).Elem()
+	// Extract internal interface{} value
+	return readField(valueKey), true
+}
+
+// maxIndirections limits how many pointers and interfaces layerStruct will
+// follow, in order to guard against values that refer to themselves.
+const maxIndirections = 16
+
+// layerStruct takes a value, typically a context, and returns the struct that
+// implements it, and if such a struct exists. Any number of pointers and
+// interfaces wrapping the struct are followed. A passed nil value will return
+// false.
+//
+// The returned struct is always addressable, so that its unexported fields
+// can be read with readField.
+func layerStruct(value interface{}) (reflect.Value, bool) {
+
+	// Guard against nil values
+	if value == nil {
+		return reflect.Value{}, false
+	}
+
+	contextVal := reflect.ValueOf(value)
+
+	// Follow pointers and interfaces, guarding against nil values
+	for index := 0; contextVal.Kind() == reflect.Pointer || contextVal.Kind() == reflect.Interface; index++ {
+		if contextVal.IsNil() || index == maxIndirections {
+			return reflect.Value{}, false
+		}
+		contextVal = contextVal.Elem()
+	}
 
 	// Guard against types with no fields (such as context.Background)
 	if contextVal.Kind() != reflect.Struct {
-		return nil, false
+		return reflect.Value{}, false
 	}
 
-	// Obtain the struct field "key"
-	valueKey := contextVal.FieldByName("key")
-	if valueKey.Kind() == reflect.Invalid {
-		return nil, false
+	// Copy struct values (as opposed to pointers to structs) so that they
+	// become addressable
+	if !contextVal.CanAddr() {
+		copied := reflect.New(contextVal.Type()).Elem()
+		copied.Set(contextVal)
+		contextVal = copied
 	}
 
-	// Obtain a reference to the "key" field so that we can actually read its internal value
-	refKey := reflect.NewAt(valueKey.Type(), unsafe.Pointer(valueKey.UnsafeAddr())).Elem()
+	return contextVal, true
+}
 
-	// Extract internal interface{} value
-	return refKey.Interface(), true
+// fieldByName returns the struct field with the given name, and if such a
+// field exists. Unlike reflect.Value.FieldByName, stepping through a nil
+// embedded pointer does not panic.
+func fieldByName(structVal reflect.Value, name string) (reflect.Value, bool) {
+	field, found := structVal.Type().FieldByName(name)
+	if !found {
+		return reflect.Value{}, false
+	}
+
+	fieldVal, err := structVal.FieldByIndexErr(field.Index)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+
+	return fieldVal, true
+}
+
+// readField returns the value of the given addressable struct field, even if
+// that field is unexported.
+func readField(field reflect.Value) interface{} {
+	if field.CanInterface() {
+		return field.Interface()
+	}
+
+	// Obtain a reference to the field so that we can actually read its internal value
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Interface()
+}
+
+// same reports if both contexts are the same context. Unlike comparing with
+// ==, this does not panic for contexts whose type is not comparable, which
+// are never considered to be the same.
+func same(a, b context.Context) bool {
+	return identical(a, b)
+}
+
+// identical reports if both values are equal according to ==, but returns
+// false instead of panicking when the values are not comparable.
+func identical(a, b interface{}) (equal bool) {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+
+	// Comparable types may still contain interfaces holding values that are
+	// not comparable
+	defer func() {
+		if recover() != nil {
+			equal = false
+		}
+	}()
+
+	return a == b
 }
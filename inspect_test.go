@@ -7,6 +7,8 @@ package contents
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"reflect"
 	"testing"
 	"time"
 
@@ -79,6 +81,72 @@ func TestUnwrap(t *testing.T) {
 				return nil, &brokenContext{"this is a broken context"}
 			},
 		},
+		{
+			title: "struct value context",
+			wrapper: func() (context.Context, context.Context) {
+				original := context.Background()
+				return original, structValueContext{original, "key", []string{"value"}}
+			},
+		},
+		{
+			title: "interface field context",
+			wrapper: func() (context.Context, context.Context) {
+				original := context.WithValue(context.Background(), "key", "value")
+				return original, &interfaceFieldContext{Context: original}
+			},
+		},
+		{
+			title: "nested interface field context",
+			wrapper: func() (context.Context, context.Context) {
+				original := stringerContext{context.WithValue(context.Background(), "key", "value")}
+				return original, &nestedInterfaceContext{Context: original}
+			},
+		},
+		{
+			title: "nil interface field context",
+			wrapper: func() (context.Context, context.Context) {
+				return nil, &interfaceFieldContext{}
+			},
+		},
+		{
+			title: "unexported embedded context",
+			wrapper: func() (context.Context, context.Context) {
+				original := context.Background()
+				return original, &unexportedEmbeddedContext{embeddedContext{original, "key"}}
+			},
+		},
+		{
+			title: "nil embedded pointer context",
+			wrapper: func() (context.Context, context.Context) {
+				return nil, &nilEmbeddedContext{}
+			},
+		},
+		{
+			title: "nil pointer context",
+			wrapper: func() (context.Context, context.Context) {
+				return nil, (*brokenContext)(nil)
+			},
+		},
+		{
+			title: "self referencing context",
+			wrapper: func() (context.Context, context.Context) {
+				ctx := &interfaceFieldContext{}
+				ctx.Context = ctx
+				return nil, ctx
+			},
+		},
+		{
+			title: "func context",
+			wrapper: func() (context.Context, context.Context) {
+				return nil, funcContext(func() {})
+			},
+		},
+		{
+			title: "map context",
+			wrapper: func() (context.Context, context.Context) {
+				return nil, mapContext{"key": "value"}
+			},
+		},
 	}
 
 	for index, test := range tests {
@@ -207,6 +275,34 @@ func TestKey(t *testing.T) {
 				return ctx
 			}(),
 		},
+		{
+			title: "struct value context",
+			ctx:   structValueContext{context.Background(), "key", []string{"value"}},
+			key:   "key",
+			found: true,
+		},
+		{
+			title: "unexported embedded context",
+			ctx:   &unexportedEmbeddedContext{embeddedContext{context.Background(), 9001}},
+			key:   9001,
+			found: true,
+		},
+		{
+			title: "nil embedded pointer context",
+			ctx:   &nilEmbeddedContext{},
+		},
+		{
+			title: "nil pointer context",
+			ctx:   (*structValueContext)(nil),
+		},
+		{
+			title: "func context",
+			ctx:   funcContext(func() {}),
+		},
+		{
+			title: "map context",
+			ctx:   mapContext{"key": "value"},
+		},
 	}
 
 	for index, test := range tests {
@@ -227,6 +323,161 @@ func TestKey(t *testing.T) {
 
 }
 
+// structValueContext is implemented by a struct value rather than a pointer,
+// and is not comparable.
+type structValueContext struct {
+	context.Context
+	key interface{}
+	val []string
+}
+
+// fieldContext stores its parent and a key in fields, but does not delegate
+// any of its methods to its parent.
+type fieldContext struct {
+	Context context.Context
+	key     interface{}
+}
+
+func (*fieldContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (*fieldContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (*fieldContext) Err() error {
+	return nil
+}
+
+func (*fieldContext) Value(key interface{}) interface{} {
+	return nil
+}
+
+// interfaceFieldContext stores its parent in an interface of a different type.
+type interfaceFieldContext struct {
+	Context interface{}
+}
+
+func (c *interfaceFieldContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (c *interfaceFieldContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (c *interfaceFieldContext) Err() error {
+	return nil
+}
+
+func (c *interfaceFieldContext) Value(key interface{}) interface{} {
+	return nil
+}
+
+// nestedInterfaceContext stores its parent in an interface that embeds
+// context.Context.
+type nestedInterfaceContext struct {
+	Context interface {
+		context.Context
+		fmt.Stringer
+	}
+}
+
+func (c *nestedInterfaceContext) Deadline() (deadline time.Time, ok bool) {
+	return c.Context.Deadline()
+}
+
+func (c *nestedInterfaceContext) Done() <-chan struct{} {
+	return c.Context.Done()
+}
+
+func (c *nestedInterfaceContext) Err() error {
+	return c.Context.Err()
+}
+
+func (c *nestedInterfaceContext) Value(key interface{}) interface{} {
+	return c.Context.Value(key)
+}
+
+type stringerContext struct {
+	context.Context
+}
+
+func (stringerContext) String() string {
+	return "stringer"
+}
+
+// unexportedEmbeddedContext has its parent and key promoted from an unexported
+// embedded struct.
+type unexportedEmbeddedContext struct {
+	embeddedContext
+}
+
+type embeddedContext struct {
+	context.Context
+	key interface{}
+}
+
+// nilEmbeddedContext has its parent and key promoted through a nil pointer.
+type nilEmbeddedContext struct {
+	*embeddedContext
+}
+
+func (*nilEmbeddedContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (*nilEmbeddedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (*nilEmbeddedContext) Err() error {
+	return nil
+}
+
+func (*nilEmbeddedContext) Value(key interface{}) interface{} {
+	return nil
+}
+
+// funcContext is implemented by a function type.
+type funcContext func()
+
+func (funcContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (funcContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (funcContext) Err() error {
+	return nil
+}
+
+func (funcContext) Value(key interface{}) interface{} {
+	return nil
+}
+
+// mapContext is implemented by a map type.
+type mapContext map[interface{}]interface{}
+
+func (mapContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (mapContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (mapContext) Err() error {
+	return nil
+}
+
+func (c mapContext) Value(key interface{}) interface{} {
+	return c[key]
+}
+
 type brokenContext struct {
 	// Contest is specifically *not* of type context.Context
 	Context string
@@ -247,3 +498,88 @@ func (*brokenContext) Err() error {
 func (*brokenContext) Value(key interface{}) interface{} {
 	return nil
 }
+
+func TestZoo(t *testing.T) {
+
+	selfReferencing := &interfaceFieldContext{}
+	selfReferencing.Context = selfReferencing
+
+	cycleA := &interfaceFieldContext{}
+	cycleB := &interfaceFieldContext{Context: cycleA}
+	cycleA.Context = cycleB
+
+	keyedCycle := &fieldContext{key: "key-a"}
+	keyedCycle.Context = &fieldContext{Context: keyedCycle, key: "key-b"}
+
+	tests := []struct {
+		title string
+		ctx   context.Context
+
+		// panics is true if the methods of the context itself panic
+		panics bool
+	}{
+		{"broken context", &brokenContext{"this is a broken context"}, false},
+		{"struct value context", structValueContext{context.Background(), "key", []string{"value"}}, false},
+		{"nil key context", &structValueContext{context.Background(), nil, []string{"value"}}, false},
+		{"uncomparable key context", &structValueContext{context.Background(), []int{1}, []string{"value"}}, false},
+		{"interface field context", &interfaceFieldContext{Context: context.Background()}, false},
+		{"nested interface field context", &nestedInterfaceContext{Context: stringerContext{context.Background()}}, false},
+		{"nil interface field context", &interfaceFieldContext{}, false},
+		{"unexported embedded context", &unexportedEmbeddedContext{embeddedContext{context.Background(), "key"}}, false},
+		{"nil embedded pointer context", &nilEmbeddedContext{}, false},
+		{"nil pointer context", (*structValueContext)(nil), true},
+		{"self referencing context", selfReferencing, false},
+		{"two cycle context", cycleA, false},
+		{"two cycle context with keys", keyedCycle, false},
+		{"func context", funcContext(func() {}), false},
+		{"map context", mapContext{"key": "value"}, false},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			// Wrap every context, so that it is reached by walking the chain
+			ctx := context.WithValue(test.ctx, "outer", "value")
+
+			assert.NotPanics(t, func() {
+				Unwrap(ctx)
+				Key(ctx)
+				Keys(ctx)
+				Pairs(ctx)
+				Depth(ctx)
+				IsAncestor(test.ctx, ctx)
+				CommonAncestor(test.ctx, ctx)
+				Size(ctx)
+				Fingerprint(ctx)
+				Map(ctx)
+				AfterFuncs(ctx)
+				DiscoverKeys(ctx)
+				Rebase(ctx, nil)
+				Filter(ctx, func(Pair) bool { return true })
+				Without(ctx, "key", []int{1})
+				Compact(ctx)
+				LogValue(ctx)
+				Encode(ctx)
+				Inject(ctx, http.Header{})
+			})
+
+			// Helpers that call the methods of every layer can only be used
+			// with contexts whose methods work
+			if !test.panics {
+				assert.NotPanics(t, func() {
+					Snapshot(ctx)
+					Probe(ctx, "key", "outer")
+					Diff(ctx, test.ctx)
+				})
+			}
+
+			assert.True(t, IsAncestor(test.ctx, ctx) || !reflect.TypeOf(test.ctx).Comparable())
+
+		})
+
+	}
+
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package key provides typed, self-describing context keys.
+//
+// A key created with New carries a human readable name, along with the path
+// of the package that created it. Keys print as "path/to/pkg.name", so that
+// helpers such as contents.Pairs show where a value came from, rather than
+// an anonymous "{}".
+//
+//	var userID = key.New[string]("userID")
+//
+//	ctx = userID.With(ctx, "alice")
+//
+//	id, found := userID.Get(ctx)
+package key
+
+import (
+	"context"
+	"net/url"
+	"runtime"
+	"strings"
+)
+
+// Key is a context key that holds values of type T. Every key returned by New
+// is distinct, even if it shares a name with another key.
+type Key[T any] struct {
+	name string
+	pkg  string
+}
+
+// New returns a new key with the given name. The package path of the caller
+// is recorded alongside the name.
+func New[T any](name string) *Key[T] {
+	return &Key[T]{
+		name: name,
+		pkg:  callerPackage(2),
+	}
+}
+
+// Name returns the name that the key was created with.
+func (k *Key[T]) Name() string {
+	return k.name
+}
+
+// Package returns the path of the package that created the key, or an empty
+// string if it could not be determined.
+func (k *Key[T]) Package() string {
+	return k.pkg
+}
+
+// String returns the package path and name of the key, such as
+// "github.com/example/auth.userID".
+func (k *Key[T]) String() string {
+	if k.pkg == "" {
+		return k.name
+	}
+	return k.pkg + "." + k.name
+}
+
+// MarshalText implements encoding.TextMarshaler, so that keys are rendered by
+// their String representation when exported as JSON.
+func (k *Key[T]) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// With returns a copy of the given context in which the key is associated
+// with the given value.
+func (k *Key[T]) With(ctx context.Context, value T) context.Context {
+	return context.WithValue(ctx, k, value)
+}
+
+// Get returns the value associated with the key in the given context, and if
+// such a value exists. A passed nil context will return the zero value and
+// false.
+func (k *Key[T]) Get(ctx context.Context) (T, bool) {
+	var zero T
+
+	// Guard against nil contexts
+	if ctx == nil {
+		return zero, false
+	}
+
+	value, ok := ctx.Value(k).(T)
+	if !ok {
+		return zero, false
+	}
+
+	return value, true
+}
+
+// callerPackage returns the package path of the function that is skip frames
+// above the caller, or an empty string if it could not be determined.
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+
+	return packagePath(fn.Name())
+}
+
+// packagePath returns the package path of the given function name, such as
+// "github.com/example/pkg" for "github.com/example/pkg.(*T).Method.func1".
+//
+// The package path ends at the first "." after the final "/". Dots in the
+// final element of a package path, such as "gopkg.in/yaml.v3", are always
+// escaped as "%2e" in function names, and are unescaped here.
+func packagePath(name string) string {
+	// Drop type arguments, which may themselves contain package paths
+	if bracket := strings.Index(name, "["); bracket >= 0 {
+		name = name[:bracket]
+	}
+
+	slash := strings.LastIndex(name, "/")
+	end := len(name)
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		end = slash + 1 + dot
+	}
+
+	return unescape(name[:end])
+}
+
+// unescape reverses the escaping applied to package paths in function names,
+// which may have been applied more than once.
+func unescape(path string) string {
+	for strings.Contains(path, "%") {
+		unescaped, err := url.PathUnescape(path)
+		if err != nil || unescaped == path {
+			break
+		}
+		path = unescaped
+	}
+
+	return path
+}
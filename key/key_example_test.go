@@ -0,0 +1,30 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package key_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshdk/contents"
+	"github.com/joshdk/contents/key"
+)
+
+var userID = key.New[string]("userID")
+
+func ExampleNew() {
+	ctx := userID.With(context.Background(), "alice")
+
+	if id, found := userID.Get(ctx); found {
+		fmt.Printf("Value of userID is %q\n", id)
+	}
+
+	for _, pair := range contents.Pairs(ctx) {
+		fmt.Printf("Context contains %v → %q\n", pair.Key, pair.Value)
+	}
+	// Output:
+	// Value of userID is "alice"
+	// Context contains github.com/joshdk/contents/key_test.userID → "alice"
+}
@@ -0,0 +1,183 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package key
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var packageKey = New[string]("packageKey")
+
+func TestKey(t *testing.T) {
+
+	local := New[int]("local")
+
+	tests := []struct {
+		title  string
+		name   string
+		pkg    string
+		str    string
+		actual interface {
+			Name() string
+			Package() string
+			String() string
+		}
+	}{
+		{
+			title:  "package key",
+			name:   "packageKey",
+			pkg:    "github.com/joshdk/contents/key",
+			str:    "github.com/joshdk/contents/key.packageKey",
+			actual: packageKey,
+		},
+		{
+			title:  "local key",
+			name:   "local",
+			pkg:    "github.com/joshdk/contents/key",
+			str:    "github.com/joshdk/contents/key.local",
+			actual: local,
+		},
+		{
+			title:  "empty package",
+			name:   "empty",
+			str:    "empty",
+			actual: &Key[bool]{name: "empty"},
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			assert.Equal(t, test.name, test.actual.Name())
+			assert.Equal(t, test.pkg, test.actual.Package())
+			assert.Equal(t, test.str, test.actual.String())
+			assert.Equal(t, test.str, fmt.Sprint(test.actual))
+
+		})
+
+	}
+
+}
+
+func TestKeyWithGet(t *testing.T) {
+
+	first := New[string]("name")
+	second := New[string]("name")
+
+	value, found := first.Get(nil)
+	assert.Equal(t, "", value)
+	assert.False(t, found)
+
+	ctx := first.With(context.Background(), "first")
+
+	value, found = first.Get(ctx)
+	assert.Equal(t, "first", value)
+	assert.True(t, found)
+
+	value, found = second.Get(ctx)
+	assert.Equal(t, "", value)
+	assert.False(t, found)
+
+	ctx = context.WithValue(ctx, second, 2)
+
+	value, found = second.Get(ctx)
+	assert.Equal(t, "", value)
+	assert.False(t, found)
+
+}
+
+func TestKeyMarshal(t *testing.T) {
+
+	data, err := json.Marshal(map[string]interface{}{
+		"key": packageKey,
+	})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"key": "github.com/joshdk/contents/key.packageKey"}`, string(data))
+
+}
+
+func TestPackagePath(t *testing.T) {
+
+	tests := []struct {
+		title string
+		name  string
+		path  string
+	}{
+		{
+			title: "empty name",
+			name:  "",
+			path:  "",
+		},
+		{
+			title: "main function",
+			name:  "main.main",
+			path:  "main",
+		},
+		{
+			title: "package function",
+			name:  "github.com/joshdk/contents/key.New",
+			path:  "github.com/joshdk/contents/key",
+		},
+		{
+			title: "generic function",
+			name:  "github.com/joshdk/contents/key.New[...]",
+			path:  "github.com/joshdk/contents/key",
+		},
+		{
+			title: "type arguments with package paths",
+			name:  "example.com/pkg.New[example.com/other.T]",
+			path:  "example.com/pkg",
+		},
+		{
+			title: "pointer method",
+			name:  "github.com/joshdk/contents/key.(*Key[...]).With",
+			path:  "github.com/joshdk/contents/key",
+		},
+		{
+			title: "closure",
+			name:  "github.com/joshdk/contents/key.TestKey.func1",
+			path:  "github.com/joshdk/contents/key",
+		},
+		{
+			title: "escaped dot",
+			name:  "gopkg.in/yaml%2ev3.(*Decoder).Decode",
+			path:  "gopkg.in/yaml.v3",
+		},
+		{
+			title: "doubly escaped dot",
+			name:  "gopkg.in/yaml%252ev3.init.func1",
+			path:  "gopkg.in/yaml.v3",
+		},
+		{
+			title: "closure in function named like a version",
+			name:  "example.com/pkg.v2.func1",
+			path:  "example.com/pkg",
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			path := packagePath(test.name)
+
+			assert.Equal(t, test.path, path)
+
+		})
+
+	}
+
+}
@@ -0,0 +1,102 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"strconv"
+	"sync"
+)
+
+// Histogram bucket upper bounds used by NewExpvar. CountBuckets is used for
+// the depth, values, and shadowed metrics, and DeadlineBuckets for the
+// deadline metric. They may be changed before calling NewExpvar.
+var (
+	CountBuckets    = []float64{1, 2, 4, 8, 16, 32, 64, 128, 256}
+	DeadlineBuckets = []float64{0, 0.01, 0.1, 1, 10, 60, 300, 3600}
+)
+
+// Expvar is a Recorder that publishes a histogram for each metric via the
+// expvar package.
+type Expvar struct {
+	vars *expvar.Map
+}
+
+// NewExpvar returns a recorder that publishes its histograms under the given
+// expvar name. As with expvar.Publish, it panics if the name is already in
+// use.
+func NewExpvar(name string) *Expvar {
+	vars := expvar.NewMap(name)
+
+	vars.Set(MetricDepth, newHistogram(CountBuckets))
+	vars.Set(MetricValues, newHistogram(CountBuckets))
+	vars.Set(MetricShadowed, newHistogram(CountBuckets))
+	vars.Set(MetricDeadline, newHistogram(DeadlineBuckets))
+
+	return &Expvar{vars: vars}
+}
+
+// Observe implements Recorder. Observations of unknown metrics are ignored.
+func (e *Expvar) Observe(metric string, value float64) {
+	if hist, ok := e.vars.Get(metric).(*histogram); ok {
+		hist.observe(value)
+	}
+}
+
+// histogram is a cumulative histogram that implements expvar.Var.
+type histogram struct {
+	mu      sync.Mutex
+	bounds  []float64
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds:  bounds,
+		buckets: make([]uint64, len(bounds)),
+	}
+}
+
+func (h *histogram) observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.count++
+	h.sum += value
+
+	for index, bound := range h.bounds {
+		if value <= bound {
+			h.buckets[index]++
+		}
+	}
+}
+
+// String renders the histogram as JSON, with every bucket counting the number
+// of observations less than or equal to its bound.
+func (h *histogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets := make(map[string]uint64, len(h.bounds)+1)
+	for index, bound := range h.bounds {
+		buckets[strconv.FormatFloat(bound, 'g', -1, 64)] = h.buckets[index]
+	}
+	buckets["+Inf"] = h.count
+
+	data, _ := json.Marshal(struct {
+		Count   uint64            `json:"count"`
+		Sum     float64           `json:"sum"`
+		Buckets map[string]uint64 `json:"buckets"`
+	}{
+		Count:   h.count,
+		Sum:     h.sum,
+		Buckets: buckets,
+	})
+
+	return string(data)
+}
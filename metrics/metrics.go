@@ -0,0 +1,136 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+// Package metrics records statistics about the shape of contexts, such as how
+// deeply they are wrapped and how many values they carry, so that context
+// bloat can be spotted across services.
+//
+// Observations are passed to a Recorder. The Expvar recorder publishes them as
+// histograms via the expvar package, and other metrics systems, such as
+// Prometheus, can be supported by implementing Recorder.
+//
+//	sampler := metrics.NewSampler(metrics.NewExpvar("contexts"), 10)
+//
+//	http.ListenAndServe(":8080", sampler.Middleware(handler))
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/joshdk/contents"
+)
+
+// Names of the metrics that are passed to a Recorder.
+const (
+	// MetricDepth is the number of times a context can be unwrapped.
+	MetricDepth = "depth"
+
+	// MetricValues is the number of key:value pairs a context contains.
+	MetricValues = "values"
+
+	// MetricShadowed is the number of key:value pairs that are shadowed by a
+	// later pair with the same key.
+	MetricShadowed = "shadowed"
+
+	// MetricDeadline is the number of seconds remaining until the deadline of
+	// a context. It is only recorded for contexts that have a deadline.
+	MetricDeadline = "deadline_seconds"
+)
+
+// Recorder receives observations of context shape statistics.
+type Recorder interface {
+	Observe(metric string, value float64)
+}
+
+// Shape describes the shape of a single context.
+type Shape struct {
+	Depth       int
+	Values      int
+	Shadowed    int
+	Deadline    time.Duration
+	HasDeadline bool
+}
+
+// Measure returns the shape of the given context.
+func Measure(ctx context.Context) Shape {
+	shape := Shape{
+		Depth: contents.Depth(ctx),
+	}
+
+	seen := map[interface{}]bool{}
+	for _, key := range contents.Keys(ctx) {
+		shape.Values++
+
+		// Guard against keys that can not be used in a map, which can never
+		// shadow another key
+		if key != nil && !reflect.TypeOf(key).Comparable() {
+			continue
+		}
+
+		if seen[key] {
+			shape.Shadowed++
+		}
+		seen[key] = true
+	}
+
+	if ctx != nil {
+		if deadline, ok := ctx.Deadline(); ok {
+			shape.Deadline = time.Until(deadline)
+			shape.HasDeadline = true
+		}
+	}
+
+	return shape
+}
+
+// Sampler measures a sample of the contexts that are given to it, and passes
+// their shape to a Recorder.
+type Sampler struct {
+	recorder Recorder
+	every    uint64
+	count    atomic.Uint64
+}
+
+// NewSampler returns a sampler that records the shape of one of every given
+// number of contexts. A value of 1 or less records every context.
+func NewSampler(recorder Recorder, every int) *Sampler {
+	if every < 1 {
+		every = 1
+	}
+
+	return &Sampler{
+		recorder: recorder,
+		every:    uint64(every),
+	}
+}
+
+// Sample records the shape of the given context, if it has been selected for
+// sampling.
+func (s *Sampler) Sample(ctx context.Context) {
+	if (s.count.Add(1)-1)%s.every != 0 {
+		return
+	}
+
+	shape := Measure(ctx)
+
+	s.recorder.Observe(MetricDepth, float64(shape.Depth))
+	s.recorder.Observe(MetricValues, float64(shape.Values))
+	s.recorder.Observe(MetricShadowed, float64(shape.Shadowed))
+	if shape.HasDeadline {
+		s.recorder.Observe(MetricDeadline, shape.Deadline.Seconds())
+	}
+}
+
+// Middleware returns a handler that samples the context of each request,
+// before passing it on to the given handler.
+func (s *Sampler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Sample(r.Context())
+		next.ServeHTTP(w, r)
+	})
+}
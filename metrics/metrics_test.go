@@ -0,0 +1,194 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keyedContext holds a key in a field, which need not be comparable.
+type keyedContext struct {
+	context.Context
+	key interface{}
+}
+
+func TestMeasure(t *testing.T) {
+
+	tests := []struct {
+		title    string
+		ctx      context.Context
+		shape    Shape
+		deadline bool
+	}{
+		{
+			title: "nil context",
+			ctx:   nil,
+		},
+		{
+			title: "background context",
+			ctx:   context.Background(),
+		},
+		{
+			title: "duplicate key context",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				ctx = context.WithValue(ctx, "key-1", "VALUE-ONE")
+				ctx = context.WithValue(ctx, "key-1", "value-one")
+				return ctx
+			}(),
+			shape: Shape{
+				Depth:    4,
+				Values:   4,
+				Shadowed: 2,
+			},
+		},
+		{
+			title: "uncomparable key context",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = &keyedContext{ctx, []int{1}}
+				ctx = context.WithValue(ctx, "key-1", "VALUE-ONE")
+				return ctx
+			}(),
+			shape: Shape{
+				Depth:    3,
+				Values:   3,
+				Shadowed: 1,
+			},
+		},
+		{
+			title: "timeout context with keys",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx, cancel := context.WithTimeout(ctx, time.Hour)
+				_ = cancel
+				return ctx
+			}(),
+			shape: Shape{
+				Depth:       2,
+				Values:      1,
+				HasDeadline: true,
+			},
+			deadline: true,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			shape := Measure(test.ctx)
+
+			if test.deadline {
+				assert.InDelta(t, time.Hour, shape.Deadline, float64(time.Minute))
+				shape.Deadline = 0
+			}
+
+			assert.Equal(t, test.shape, shape)
+
+		})
+
+	}
+
+}
+
+type observation struct {
+	metric string
+	value  float64
+}
+
+type recorder []observation
+
+func (r *recorder) Observe(metric string, value float64) {
+	*r = append(*r, observation{metric, value})
+}
+
+func TestSampler(t *testing.T) {
+
+	var observed recorder
+	sampler := NewSampler(&observed, 2)
+
+	ctx := context.WithValue(context.Background(), "key", "value")
+
+	handler := sampler.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "value", r.Context().Value("key"))
+	}))
+
+	for count := 0; count < 3; count++ {
+		req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.Equal(t, recorder{
+		{MetricDepth, 1},
+		{MetricValues, 1},
+		{MetricShadowed, 0},
+		{MetricDepth, 1},
+		{MetricValues, 1},
+		{MetricShadowed, 0},
+	}, observed)
+
+}
+
+// expvarRuns makes the names published by TestExpvar unique, as expvar
+// panics when a name is published more than once.
+var expvarRuns int
+
+func TestExpvar(t *testing.T) {
+
+	expvarRuns++
+	name := fmt.Sprintf("contents_test_%d", expvarRuns)
+
+	recorder := NewExpvar(name)
+	sampler := NewSampler(recorder, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	ctx = context.WithValue(ctx, "key", "value")
+	ctx = context.WithValue(ctx, "key", "VALUE")
+
+	sampler.Sample(ctx)
+	sampler.Sample(context.Background())
+	recorder.Observe("unknown", 1)
+
+	var published map[string]struct {
+		Count   uint64            `json:"count"`
+		Sum     float64           `json:"sum"`
+		Buckets map[string]uint64 `json:"buckets"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &published))
+
+	assert.Len(t, published, 4)
+
+	assert.Equal(t, uint64(2), published[MetricDepth].Count)
+	assert.Equal(t, 3.0, published[MetricDepth].Sum)
+	assert.Equal(t, uint64(1), published[MetricDepth].Buckets["1"])
+	assert.Equal(t, uint64(1), published[MetricDepth].Buckets["2"])
+	assert.Equal(t, uint64(2), published[MetricDepth].Buckets["4"])
+	assert.Equal(t, uint64(2), published[MetricDepth].Buckets["+Inf"])
+
+	assert.Equal(t, 1.0, published[MetricShadowed].Sum)
+
+	assert.Equal(t, uint64(1), published[MetricDeadline].Count)
+	assert.Equal(t, uint64(0), published[MetricDeadline].Buckets["10"])
+	assert.Equal(t, uint64(1), published[MetricDeadline].Buckets["60"])
+
+}
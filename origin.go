@@ -0,0 +1,162 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+	"weak"
+)
+
+// Origin describes where a context layer was created.
+type Origin struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// String returns the file and line of the origin, with the file shortened to
+// its final directory, such as "auth/middleware.go:88".
+func (o Origin) String() string {
+	file := filepath.Join(filepath.Base(filepath.Dir(o.File)), filepath.Base(o.File))
+	return file + ":" + strconv.Itoa(o.Line)
+}
+
+var originsEnabled atomic.Bool
+
+// TrackOrigins enables or disables the recording of origins by WithValue,
+// WithCancel, WithDeadline, and WithTimeout. Tracking is disabled by default,
+// in which case those functions cost no more than their equivalents from the
+// context package.
+func TrackOrigins(enabled bool) {
+	originsEnabled.Store(enabled)
+}
+
+// originEntry is a recorded origin, along with a weak reference to the layer
+// that it belongs to.
+type originEntry struct {
+	layer  weak.Pointer[byte]
+	origin Origin
+}
+
+// origins maps the address of a layer to its originEntry. Layers are not kept
+// alive by this table, and their entries are removed once they are collected.
+var origins sync.Map
+
+// OriginOf returns where the given context layer was created, and if that is
+// known. Only layers created by WithValue, WithCancel, WithDeadline, and
+// WithTimeout while TrackOrigins is enabled have a known origin.
+func OriginOf(ctx context.Context) (Origin, bool) {
+	ptr := layerPointer(ctx)
+	if ptr == nil {
+		return Origin{}, false
+	}
+
+	entry, found := origins.Load(uintptr(unsafe.Pointer(ptr)))
+	if !found {
+		return Origin{}, false
+	}
+
+	// Guard against a different layer that was allocated at the same address
+	if entry.(originEntry).layer.Value() != ptr {
+		return Origin{}, false
+	}
+
+	return entry.(originEntry).origin, true
+}
+
+// WithValue is equivalent to context.WithValue, but records the origin of the
+// returned layer if TrackOrigins is enabled.
+func WithValue(parent context.Context, key, val interface{}) context.Context {
+	ctx := context.WithValue(parent, key, val)
+	recordOrigin(ctx)
+	return ctx
+}
+
+// WithCancel is equivalent to context.WithCancel, but records the origin of
+// the returned layer if TrackOrigins is enabled.
+func WithCancel(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	recordOrigin(ctx)
+	return ctx, cancel
+}
+
+// WithDeadline is equivalent to context.WithDeadline, but records the origin
+// of the returned layer if TrackOrigins is enabled.
+func WithDeadline(parent context.Context, d time.Time) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithDeadline(parent, d)
+	recordOrigin(ctx)
+	return ctx, cancel
+}
+
+// WithTimeout is equivalent to context.WithTimeout, but records the origin of
+// the returned layer if TrackOrigins is enabled.
+func WithTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	recordOrigin(ctx)
+	return ctx, cancel
+}
+
+// recordOrigin records the caller of the function that called recordOrigin as
+// the origin of the given layer.
+func recordOrigin(ctx context.Context) {
+	if !originsEnabled.Load() {
+		return
+	}
+
+	ptr := layerPointer(ctx)
+	if ptr == nil {
+		return
+	}
+
+	pc, file, line, ok := runtime.Caller(2)
+	if !ok {
+		return
+	}
+
+	origin := Origin{
+		File: file,
+		Line: line,
+	}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		origin.Function = fn.Name()
+	}
+
+	addr := uintptr(unsafe.Pointer(ptr))
+	origins.Store(addr, originEntry{
+		layer:  weak.Make(ptr),
+		origin: origin,
+	})
+
+	// Remove the entry once the layer is collected, unless it has since been
+	// replaced by a layer allocated at the same address
+	runtime.AddCleanup(ptr, func(addr uintptr) {
+		if entry, found := origins.Load(addr); found && entry.(originEntry).layer.Value() == nil {
+			origins.CompareAndDelete(addr, entry)
+		}
+	}, addr)
+}
+
+// layerPointer returns a pointer to the given layer, or nil if the layer is
+// not implemented by a pointer type.
+func layerPointer(ctx context.Context) *byte {
+	if ctx == nil {
+		return nil
+	}
+
+	val := reflect.ValueOf(ctx)
+	if val.Kind() != reflect.Pointer || val.IsNil() {
+		return nil
+	}
+
+	return (*byte)(val.UnsafePointer())
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrigin(t *testing.T) {
+
+	TrackOrigins(true)
+	defer TrackOrigins(false)
+
+	_, _, line, _ := runtime.Caller(0)
+
+	ctx := WithValue(context.Background(), "key", "value")
+	ctx, cancel := WithCancel(ctx)
+	defer cancel()
+	ctx, cancel = WithDeadline(ctx, time.Now().Add(time.Hour))
+	defer cancel()
+	ctx, cancel = WithTimeout(ctx, time.Hour)
+	defer cancel()
+
+	var lines []int
+	for layer := ctx; layer != nil; layer = Unwrap(layer) {
+		origin, found := OriginOf(layer)
+		if !found {
+			continue
+		}
+
+		assert.True(t, strings.HasSuffix(origin.File, "/origin_test.go"), origin.File)
+		assert.Equal(t, "github.com/joshdk/contents.TestOrigin", origin.Function)
+		assert.True(t, strings.HasSuffix(origin.String(), "/origin_test.go:"+strconv.Itoa(origin.Line)), origin.String())
+		lines = append(lines, origin.Line)
+	}
+
+	assert.Equal(t, []int{line + 7, line + 5, line + 3, line + 2}, lines)
+
+}
+
+func TestOriginDisabled(t *testing.T) {
+
+	ctx := WithValue(context.Background(), "key", "value")
+
+	_, found := OriginOf(ctx)
+	assert.False(t, found)
+
+	TrackOrigins(true)
+	defer TrackOrigins(false)
+
+	for _, ctx := range []context.Context{nil, context.Background(), context.WithValue(ctx, "key", "value")} {
+		_, found := OriginOf(ctx)
+		assert.False(t, found)
+	}
+
+}
+
+func TestOriginCollected(t *testing.T) {
+
+	TrackOrigins(true)
+	defer TrackOrigins(false)
+
+	ctx := WithValue(context.Background(), "key", "value")
+	addr := uintptrOf(ctx)
+
+	_, found := origins.Load(addr)
+	require.True(t, found)
+
+	ctx = nil
+	for attempt := 0; attempt < 100; attempt++ {
+		runtime.GC()
+		if _, found := origins.Load(addr); !found {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Error("origin was not removed after the layer was collected")
+
+}
+
+func uintptrOf(ctx context.Context) uintptr {
+	return uintptr(unsafe.Pointer(layerPointer(ctx)))
+}
@@ -0,0 +1,90 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"sync"
+)
+
+func init() {
+	// Label sets are stored by pprof.WithLabels under a private key, so one
+	// is obtained from a labeled context in order to describe it
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("", ""))
+	if key, found := Key(ctx); found {
+		Describe(key, "pprof labels", "profiler labels set by pprof.WithLabels")
+	}
+}
+
+// ProfileLabels returns the profiler labels attached to the given context with
+// pprof.WithLabels, or nil if there are none. A passed nil context will return
+// nil.
+func ProfileLabels(ctx context.Context) map[string]string {
+	// Guard against nil contexts
+	if ctx == nil {
+		return nil
+	}
+
+	var labels map[string]string
+
+	pprof.ForLabels(ctx, func(key, value string) bool {
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[key] = value
+		return true
+	})
+
+	return labels
+}
+
+var (
+	profileLabelsMu sync.RWMutex
+	profileLabels   = map[interface{}]string{}
+)
+
+// RegisterProfileLabel registers a profiler label for the given key. Values for
+// this key will be promoted into a label with the given name by
+// WithProfileLabels. Registering a key again replaces its previous label.
+//
+// As with context.WithValue, the given key must be comparable.
+func RegisterProfileLabel(key interface{}, label string) {
+	profileLabelsMu.Lock()
+	defer profileLabelsMu.Unlock()
+
+	profileLabels[key] = label
+}
+
+// WithProfileLabels returns a context where the value of every key registered
+// with RegisterProfileLabel is also attached as a profiler label, formatted
+// with fmt.Sprint. Existing labels with the same name are replaced, and keys
+// without a value are skipped. A passed nil context will return nil.
+//
+// The returned context can be passed to pprof.SetGoroutineLabels or pprof.Do,
+// so that profiles can be sliced by those values.
+func WithProfileLabels(ctx context.Context) context.Context {
+	// Guard against nil contexts
+	if ctx == nil {
+		return nil
+	}
+
+	profileLabelsMu.RLock()
+	defer profileLabelsMu.RUnlock()
+
+	var args []string
+	for key, label := range profileLabels {
+		if value := ctx.Value(key); value != nil {
+			args = append(args, label, fmt.Sprint(value))
+		}
+	}
+
+	if len(args) == 0 {
+		return ctx
+	}
+
+	return pprof.WithLabels(ctx, pprof.Labels(args...))
+}
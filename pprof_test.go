@@ -0,0 +1,122 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type profileKey string
+
+func TestProfileLabels(t *testing.T) {
+
+	tests := []struct {
+		title  string
+		ctx    context.Context
+		labels map[string]string
+	}{
+		{
+			title: "nil context",
+			ctx:   nil,
+		},
+		{
+			title: "background context",
+			ctx:   context.Background(),
+		},
+		{
+			title: "labeled context",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = pprof.WithLabels(ctx, pprof.Labels("tenant", "acme", "route", "/users"))
+				return ctx
+			}(),
+			labels: map[string]string{
+				"tenant": "acme",
+				"route":  "/users",
+			},
+		},
+		{
+			title: "merged labels",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = pprof.WithLabels(ctx, pprof.Labels("tenant", "acme", "route", "/users"))
+				ctx = context.WithValue(ctx, "key", "value")
+				ctx = pprof.WithLabels(ctx, pprof.Labels("route", "/orders"))
+				return ctx
+			}(),
+			labels: map[string]string{
+				"tenant": "acme",
+				"route":  "/orders",
+			},
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			labels := ProfileLabels(test.ctx)
+
+			assert.Equal(t, test.labels, labels)
+
+		})
+
+	}
+
+}
+
+func TestProfileLabelsKeyName(t *testing.T) {
+
+	ctx := pprof.WithLabels(context.Background(), pprof.Labels("tenant", "acme"))
+
+	pairs := Pairs(ctx)
+	require.Len(t, pairs, 1)
+
+	assert.Equal(t, "pprof labels", KeyName(pairs[0].Key))
+
+}
+
+func TestWithProfileLabels(t *testing.T) {
+
+	RegisterProfileLabel(profileKey("tenant"), "tenant")
+	RegisterProfileLabel(profileKey("status"), "status")
+	defer func() {
+		profileLabelsMu.Lock()
+		delete(profileLabels, profileKey("tenant"))
+		delete(profileLabels, profileKey("status"))
+		profileLabelsMu.Unlock()
+	}()
+
+	assert.Nil(t, WithProfileLabels(nil))
+
+	background := context.Background()
+	assert.Equal(t, background, WithProfileLabels(background))
+
+	ctx := context.Background()
+	ctx = pprof.WithLabels(ctx, pprof.Labels("tenant", "unknown", "route", "/users"))
+	ctx = context.WithValue(ctx, profileKey("tenant"), "acme")
+	ctx = context.WithValue(ctx, profileKey("status"), 200)
+	ctx = context.WithValue(ctx, profileKey("unregistered"), "value")
+
+	ctx = WithProfileLabels(ctx)
+
+	expected := map[string]string{
+		"tenant": "acme",
+		"status": "200",
+		"route":  "/users",
+	}
+
+	assert.Equal(t, expected, ProfileLabels(ctx))
+	assert.Equal(t, "acme", ctx.Value(profileKey("tenant")))
+
+}
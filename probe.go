@@ -0,0 +1,172 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"reflect"
+	"sort"
+)
+
+// Resolved describes a candidate key that resolved to a value, along with the
+// layer that holds that value.
+type Resolved struct {
+	Key   interface{}
+	Value interface{}
+
+	// Layer is the layer that answered ".Value(key)", and Depth is its depth.
+	Layer context.Context
+	Depth int
+}
+
+// Probe will return every candidate key that resolves to a non-nil value in
+// the given context, along with the layer that holds it. The layer is found by
+// unwrapping the context for as long as the parent resolves the key to the
+// same value, or to an equal value if values are not comparable. Results are
+// ordered from the innermost layer to the outermost, much like Pairs.
+// Candidates that are not comparable are skipped, as they can never be used
+// as a key.
+//
+// This makes values visible that are held by custom contexts, which answer
+// ".Value(key)" from something other than a "key" field, such as a map.
+func Probe(ctx context.Context, candidates ...interface{}) []Resolved {
+	var results []Resolved
+
+	// Guard against nil contexts
+	if ctx == nil {
+		return nil
+	}
+
+	layers := chain(ctx)
+
+	for _, key := range candidates {
+
+		// Guard against candidates that could never be used as a key
+		if key == nil || !reflect.TypeOf(key).Comparable() {
+			continue
+		}
+
+		value := ctx.Value(key)
+		if value == nil {
+			continue
+		}
+
+		// Walk inwards until the layer that holds the value is found
+		index := 0
+		for index+1 < len(layers) && equal(layers[index+1].Value(key), value) {
+			index++
+		}
+
+		results = append(results, Resolved{
+			Key:   key,
+			Value: value,
+			Layer: layers[index],
+			Depth: len(layers) - 1 - index,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Depth < results[j].Depth
+	})
+
+	return results
+}
+
+var contextType = reflect.TypeFor[context.Context]()
+
+// DiscoverKeys will return the keys of every map held by a field of any layer
+// of the given context, including unexported fields and fields of nested
+// structs. Duplicate keys are only returned once, and keys that are contexts
+// themselves are skipped. Passing these keys to Probe can find values held by
+// custom contexts that are backed by a map.
+func DiscoverKeys(ctx context.Context) []interface{} {
+	var keys []interface{}
+
+	for _, layer := range chain(ctx) {
+
+		// Guard against contexts that intentionally hide their parent
+		if _, ok := layer.(parentHider); ok {
+			break
+		}
+
+		// Read cancelable layers while holding their lock, as their maps,
+		// such as their children, are modified concurrently
+		if layerVal, unlock, ok := lockLayer(layer); ok {
+			keys = discoverKeys(layerVal, keys, 0)
+			unlock()
+		} else if layerVal, ok := layerStruct(layer); ok {
+			keys = discoverKeys(layerVal, keys, 0)
+		}
+	}
+
+	return keys
+}
+
+// discoverKeys appends the keys of every map held by a field of the given
+// struct to keys, skipping duplicates.
+func discoverKeys(structVal reflect.Value, keys []interface{}, depth int) []interface{} {
+	for index := 0; index < structVal.NumField(); index++ {
+		field := structVal.Field(index)
+
+		switch field.Kind() {
+		case reflect.Map:
+			if field.IsNil() {
+				continue
+			}
+
+			iter := reflect.ValueOf(readField(field)).MapRange()
+		keys:
+			for iter.Next() {
+				key := iter.Key().Interface()
+
+				// Skip maps keyed by contexts, such as the children of a
+				// cancelable context
+				if _, ok := key.(context.Context); ok {
+					continue
+				}
+
+				for _, existing := range keys {
+					if identical(existing, key) {
+						continue keys
+					}
+				}
+				keys = append(keys, key)
+			}
+
+		case reflect.Struct:
+			if depth >= maxDiscoveryDepth {
+				continue
+			}
+
+			// Other contexts are layers of their own, and are not looked inside
+			// of, unless they are embedded structs that are part of this layer
+			if field.Type().Implements(contextType) && !structVal.Type().Field(index).Anonymous {
+				continue
+			}
+
+			// Nested structs are walked in place rather than copied, as they
+			// may hold state that is modified concurrently, such as a mutex
+			keys = discoverKeys(field, keys, depth+1)
+
+		case reflect.Interface, reflect.Pointer:
+			if depth >= maxDiscoveryDepth || field.IsNil() {
+				continue
+			}
+
+			value := readField(field)
+
+			// Other contexts are layers of their own, and are not looked inside
+			if _, ok := value.(context.Context); ok {
+				continue
+			}
+
+			if nested, ok := layerStruct(value); ok {
+				keys = discoverKeys(nested, keys, depth+1)
+			}
+		}
+	}
+
+	return keys
+}
@@ -0,0 +1,123 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mapValueContext answers ".Value(key)" from a map, before falling back to its
+// parent.
+type mapValueContext struct {
+	context.Context
+	state struct {
+		values map[interface{}]interface{}
+	}
+}
+
+func newMapValueContext(parent context.Context, values map[interface{}]interface{}) *mapValueContext {
+	ctx := &mapValueContext{Context: parent}
+	ctx.state.values = values
+	return ctx
+}
+
+func (c *mapValueContext) Value(key interface{}) interface{} {
+	if value, found := c.state.values[key]; found {
+		return value
+	}
+	return c.Context.Value(key)
+}
+
+func TestProbe(t *testing.T) {
+
+	root := context.WithValue(context.Background(), "key-1", "value-1")
+	custom := newMapValueContext(root, map[interface{}]interface{}{
+		"key-2": "value-2",
+		"key-3": "value-3",
+	})
+	canceled, cancel := context.WithCancel(custom)
+	defer cancel()
+	ctx := context.WithValue(canceled, "key-3", "VALUE-THREE")
+
+	assert.Nil(t, Probe(nil, "key-1"))
+	assert.Nil(t, Probe(ctx))
+	assert.Nil(t, Probe(ctx, "missing", []string{"uncomparable"}))
+
+	assert.Equal(t, []Resolved{
+		{Key: "key-1", Value: "value-1", Layer: root, Depth: 1},
+		{Key: "key-2", Value: "value-2", Layer: custom, Depth: 2},
+		{Key: "key-3", Value: "VALUE-THREE", Layer: ctx, Depth: 4},
+	}, Probe(ctx, "key-3", "key-2", "missing", "key-1"))
+
+}
+
+func TestProbeUncomparableValues(t *testing.T) {
+
+	root := context.WithValue(context.Background(), "key-1", []string{"a"})
+	canceled, cancel := context.WithCancel(root)
+	defer cancel()
+	ctx := context.WithValue(canceled, "key-2", map[string]int{"b": 2})
+
+	assert.Equal(t, []Resolved{
+		{Key: "key-1", Value: []string{"a"}, Layer: root, Depth: 1},
+		{Key: "key-2", Value: map[string]int{"b": 2}, Layer: ctx, Depth: 3},
+	}, Probe(ctx, "key-2", "key-1"))
+
+}
+
+func TestDiscoverKeys(t *testing.T) {
+
+	root := context.WithValue(context.Background(), "key-1", "value-1")
+	custom := newMapValueContext(root, map[interface{}]interface{}{
+		"key-2": "value-2",
+	})
+	duplicate := newMapValueContext(custom, map[interface{}]interface{}{
+		"key-2": "VALUE-TWO",
+	})
+	canceled, cancel := context.WithCancel(duplicate)
+	defer cancel()
+	child, cancelChild := context.WithCancel(canceled)
+	defer cancelChild()
+
+	assert.Nil(t, DiscoverKeys(nil))
+	assert.Nil(t, DiscoverKeys(context.Background()))
+	assert.Equal(t, []interface{}{"key-2"}, DiscoverKeys(child))
+
+	assert.Equal(t, []Resolved{
+		{Key: "key-2", Value: "VALUE-TWO", Layer: duplicate, Depth: 3},
+	}, Probe(child, DiscoverKeys(child)...))
+
+	// Values hidden by Without are not discovered
+	assert.Nil(t, DiscoverKeys(Without(child)))
+
+}
+
+func TestDiscoverKeysConcurrentCancel(t *testing.T) {
+
+	root, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		for index := 0; index < 1000; index++ {
+			_, cancelChild := context.WithCancel(root)
+			cancelChild()
+		}
+	}()
+
+	for index := 0; index < 1000; index++ {
+		assert.Empty(t, DiscoverKeys(root))
+	}
+
+	wg.Wait()
+
+}
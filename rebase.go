@@ -0,0 +1,96 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// Rebase returns a context that contains every key:value pair found in
+// valuesFrom, but whose deadline and cancellation are taken from cancelFrom.
+// Values attached to cancelFrom are not visible in the returned context. A
+// passed nil cancelFrom is treated as context.Background().
+//
+// The layers of cancelFrom can not be reached with Unwrap, so Causes does not
+// list them. The cause of the cancellation is still reported by context.Cause
+// and Snapshot.
+//
+// This is useful for handing request-scoped values to work that must outlive
+// the request, but still needs to be stopped by some other context.
+func Rebase(valuesFrom, cancelFrom context.Context) context.Context {
+	if cancelFrom == nil {
+		cancelFrom = context.Background()
+	}
+
+	return rebuild(&cancelOnlyCtx{cancelFrom}, Pairs(valuesFrom))
+}
+
+// rebuild wraps the given context with each of the given pairs, in order.
+// Pairs with keys that can not be used with context.WithValue, such as those
+// reported for some custom contexts, are skipped.
+func rebuild(ctx context.Context, pairs []Pair) context.Context {
+	for _, pair := range pairs {
+		// Guard against keys that context.WithValue would panic on
+		if pair.Key == nil || !reflect.TypeOf(pair.Key).Comparable() {
+			continue
+		}
+
+		ctx = context.WithValue(ctx, pair.Key, pair.Value)
+	}
+
+	return ctx
+}
+
+// cancelCtxType is the type of the layers created by context.WithCancel.
+var cancelCtxType = func() reflect.Type {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	return reflect.TypeOf(ctx)
+}()
+
+// cancelOnlyCtx is a context that takes its deadline and cancellation from
+// the wrapped context, but hides all of its values. The wrapped context is
+// intentionally not stored in a field named "Context", and is excluded from
+// DiscoverParent, so that it can not be reached with Unwrap.
+type cancelOnlyCtx struct {
+	parent context.Context
+}
+
+func (c *cancelOnlyCtx) Deadline() (deadline time.Time, ok bool) {
+	return c.parent.Deadline()
+}
+
+func (c *cancelOnlyCtx) Done() <-chan struct{} {
+	return c.parent.Done()
+}
+
+func (c *cancelOnlyCtx) Err() error {
+	return c.parent.Err()
+}
+
+func (c *cancelOnlyCtx) Value(key interface{}) interface{} {
+	// The context package looks up the cancelable context that a context
+	// belongs to with a private key of type *int, in order to find its cause.
+	// Only that lookup is answered, so that context.Cause reports the cause
+	// of the wrapped context.
+	if _, ok := key.(*int); ok {
+		if value := c.parent.Value(key); reflect.TypeOf(value) == cancelCtxType {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// AfterFunc lets contexts derived from this one register for cancellation
+// directly with the wrapped context, instead of each starting a goroutine to
+// wait for it.
+func (c *cancelOnlyCtx) AfterFunc(f func()) func() bool {
+	return context.AfterFunc(c.parent, f)
+}
+
+func (*cancelOnlyCtx) hidesParent() {}
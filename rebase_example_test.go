@@ -0,0 +1,31 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshdk/contents"
+)
+
+func ExampleRebase() {
+	request, cancelRequest := context.WithCancel(context.Background())
+	request = context.WithValue(request, "request-id", "abc123")
+
+	server, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+
+	ctx := contents.Rebase(request, server)
+
+	// The request finishes, but the rebased context lives on
+	cancelRequest()
+
+	fmt.Printf("Value of request-id is %q\n", ctx.Value("request-id"))
+	fmt.Printf("Error is %v\n", ctx.Err())
+	// Output:
+	// Value of request-id is "abc123"
+	// Error is <nil>
+}
@@ -0,0 +1,180 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebase(t *testing.T) {
+
+	deadline := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		title      string
+		valuesFrom context.Context
+		cancelFrom context.Context
+		pairs      []Pair
+		err        error
+		deadline   bool
+	}{
+		{
+			title: "nil contexts",
+		},
+		{
+			title:      "background contexts",
+			valuesFrom: context.Background(),
+			cancelFrom: context.Background(),
+		},
+		{
+			title: "values onto background",
+			valuesFrom: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				ctx = context.WithValue(ctx, "key-2", "value-2")
+				return ctx
+			}(),
+			cancelFrom: context.Background(),
+			pairs: []Pair{
+				{"key-1", "value-1"},
+				{"key-2", "value-2"},
+			},
+		},
+		{
+			title: "values onto canceled context",
+			valuesFrom: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				return ctx
+			}(),
+			cancelFrom: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "hidden", "value")
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				return ctx
+			}(),
+			pairs: []Pair{
+				{"key-1", "value-1"},
+			},
+			err: context.Canceled,
+		},
+		{
+			title: "values onto deadline context",
+			valuesFrom: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "key-1", "value-1")
+				ctx = context.WithValue(ctx, "key-1", "VALUE-ONE")
+				return ctx
+			}(),
+			cancelFrom: func() context.Context {
+				ctx := context.Background()
+				ctx, cancel := context.WithDeadline(ctx, deadline)
+				_ = cancel
+				return ctx
+			}(),
+			pairs: []Pair{
+				{"key-1", "value-1"},
+				{"key-1", "VALUE-ONE"},
+			},
+			deadline: true,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			ctx := Rebase(test.valuesFrom, test.cancelFrom)
+
+			assert.Equal(t, test.pairs, Pairs(ctx))
+			assert.Equal(t, test.err, ctx.Err())
+
+			for _, pair := range Pairs(test.valuesFrom) {
+				assert.Equal(t, test.valuesFrom.Value(pair.Key), ctx.Value(pair.Key))
+			}
+
+			assert.Nil(t, ctx.Value("hidden"))
+
+			actual, ok := ctx.Deadline()
+			assert.Equal(t, test.deadline, ok)
+			if test.deadline {
+				assert.Equal(t, deadline, actual)
+			}
+
+		})
+
+	}
+
+}
+
+func TestRebaseCancel(t *testing.T) {
+
+	valuesFrom, cancelValues := context.WithCancel(context.Background())
+	valuesFrom = context.WithValue(valuesFrom, "key", "value")
+
+	cancelFrom, cancel := context.WithCancel(context.Background())
+
+	ctx := Rebase(valuesFrom, cancelFrom)
+
+	cancelValues()
+	assert.NoError(t, ctx.Err())
+
+	cancel()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+	assert.Equal(t, "value", ctx.Value("key"))
+
+}
+
+func TestRebaseDerivedCancel(t *testing.T) {
+
+	cancelFrom, cancel := context.WithCancel(context.Background())
+
+	ctx := Rebase(context.Background(), cancelFrom)
+
+	child, cancelChild := context.WithCancel(ctx)
+	defer cancelChild()
+
+	// The child is registered directly with cancelFrom, rather than with a
+	// goroutine waiting on it
+	assert.Len(t, layerChildren(cancelFrom), 1)
+
+	cancel()
+	<-child.Done()
+	assert.Equal(t, context.Canceled, child.Err())
+
+}
+
+func TestRebaseCause(t *testing.T) {
+
+	cause := errors.New("upstream shutdown")
+
+	cancelFrom, cancel := context.WithCancelCause(context.Background())
+	cancelFrom = context.WithValue(cancelFrom, "hidden", "value")
+
+	ctx := Rebase(context.WithValue(context.Background(), "key", "value"), cancelFrom)
+	cancel(cause)
+
+	assert.Equal(t, context.Canceled, ctx.Err())
+	assert.Equal(t, cause, context.Cause(ctx))
+	assert.Equal(t, cause, Snapshot(ctx).Cause)
+
+	// The lookup used to find the cause does not expose other values
+	key := new(int)
+	cancelFrom = context.WithValue(cancelFrom, key, "value")
+	assert.Nil(t, Rebase(context.Background(), cancelFrom).Value(key))
+
+}
@@ -0,0 +1,195 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"reflect"
+	"unsafe"
+)
+
+// Size will return an estimate of the number of bytes retained by the given
+// context. Every layer is sized, along with every object that is reachable
+// from a key or value within that layer. Objects that are reachable more than
+// once, such as a value that was added under several keys, are only counted
+// once. A passed nil context will return 0.
+//
+// Only the struct of each layer is counted, and not the objects referenced
+// by its internal fields, such as the children of a cancelable context. These
+// are not retained by the context alone, and may be modified concurrently.
+//
+// Sizes are estimated using reflection, so allocator overhead, the internals
+// of maps and channels, and objects referenced by closures are approximated
+// or not counted at all.
+func Size(ctx context.Context) int {
+	s := sizer{seen: map[objectKey]bool{}}
+	var size uintptr
+
+	for _, layer := range chain(ctx) {
+		// Size the layer struct itself, and a key and value if it has them
+		size += s.layer(layer)
+
+		if key, found := Key(layer); found {
+			size += s.boxed(key)
+			size += s.boxed(layer.Value(key))
+		}
+	}
+
+	return int(size)
+}
+
+// objectKey identifies an object by its address and type, as an object and its
+// first field share the same address.
+type objectKey struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// sizer estimates the size of objects, while counting every object only once.
+type sizer struct {
+	seen map[objectKey]bool
+}
+
+// visit reports if the object at the given address has not yet been sized,
+// and marks it as sized.
+func (s *sizer) visit(addr uintptr, typ reflect.Type) bool {
+	key := objectKey{addr, typ}
+	if s.seen[key] {
+		return false
+	}
+	s.seen[key] = true
+	return true
+}
+
+// layer returns the size of the struct that implements the given layer,
+// without walking any of its fields.
+func (s *sizer) layer(ctx context.Context) uintptr {
+	v := reflect.ValueOf(ctx)
+
+	if v.Kind() != reflect.Pointer {
+		return v.Type().Size()
+	}
+
+	if v.IsNil() || !s.visit(v.Pointer(), v.Type()) {
+		return 0
+	}
+
+	return v.Type().Elem().Size()
+}
+
+// boxed returns the size of a value that has been stored in an interface,
+// including the value itself if it had to be allocated separately.
+func (s *sizer) boxed(x interface{}) uintptr {
+	if x == nil {
+		return 0
+	}
+
+	v := reflect.ValueOf(x)
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		// These values are stored directly in the interface
+		return s.indirect(v)
+	default:
+		return v.Type().Size() + s.indirect(v)
+	}
+}
+
+// indirect returns the size of every object reachable from the given value,
+// not including the value itself.
+func (s *sizer) indirect(v reflect.Value) uintptr {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || !s.visit(v.Pointer(), v.Type()) {
+			return 0
+		}
+		return v.Type().Elem().Size() + s.indirect(v.Elem())
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Elem()
+		switch elem.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+			return s.indirect(elem)
+		default:
+			return elem.Type().Size() + s.indirect(elem)
+		}
+
+	case reflect.String:
+		if v.Len() == 0 || !s.visit(uintptr(unsafe.Pointer(unsafe.StringData(v.String()))), v.Type()) {
+			return 0
+		}
+		return uintptr(v.Len())
+
+	case reflect.Slice:
+		if v.IsNil() || !s.visit(v.Pointer(), v.Type()) {
+			return 0
+		}
+		size := uintptr(v.Cap()) * v.Type().Elem().Size()
+		if scalar(v.Type().Elem()) {
+			return size
+		}
+		for index := 0; index < v.Len(); index++ {
+			size += s.indirect(v.Index(index))
+		}
+		return size
+
+	case reflect.Array:
+		var size uintptr
+		if scalar(v.Type().Elem()) {
+			return size
+		}
+		for index := 0; index < v.Len(); index++ {
+			size += s.indirect(v.Index(index))
+		}
+		return size
+
+	case reflect.Struct:
+		var size uintptr
+		for index := 0; index < v.NumField(); index++ {
+			size += s.indirect(v.Field(index))
+		}
+		return size
+
+	case reflect.Map:
+		if v.IsNil() || !s.visit(v.Pointer(), v.Type()) {
+			return 0
+		}
+		// Approximate the map header, and a slot for every entry
+		size := uintptr(48) + uintptr(v.Len())*(v.Type().Key().Size()+v.Type().Elem().Size()+1)
+		iter := v.MapRange()
+		for iter.Next() {
+			size += s.indirect(iter.Key())
+			size += s.indirect(iter.Value())
+		}
+		return size
+
+	case reflect.Chan:
+		if v.IsNil() || !s.visit(v.Pointer(), v.Type()) {
+			return 0
+		}
+		// Approximate the channel header, and its buffer
+		return uintptr(96) + uintptr(v.Cap())*v.Type().Elem().Size()
+
+	default:
+		return 0
+	}
+}
+
+// scalar reports if values of the given type can never reference other
+// objects, and so do not need to be walked.
+func scalar(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSize(t *testing.T) {
+
+	type valueCtx struct {
+		context.Context
+		key, val interface{}
+	}
+
+	layer := int(unsafe.Sizeof(valueCtx{}))
+	big := make([]byte, 1<<20)
+	bigPointer := &[1 << 16]byte{}
+
+	tests := []struct {
+		title string
+		ctx   context.Context
+		size  int
+	}{
+		{
+			title: "nil context",
+			ctx:   nil,
+		},
+		{
+			title: "background context",
+			ctx:   context.Background(),
+		},
+		{
+			title: "pointer value",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "", bigPointer)
+				return ctx
+			}(),
+			size: layer + 16 + 1<<16,
+		},
+		{
+			title: "array value",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "k", [4096]byte{})
+				return ctx
+			}(),
+			size: layer + 16 + 1 + 4096,
+		},
+		{
+			title: "slice value",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "", big)
+				return ctx
+			}(),
+			size: layer + 16 + 24 + 1<<20,
+		},
+		{
+			title: "shared values",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "", bigPointer)
+				ctx = context.WithValue(ctx, "", bigPointer)
+				return ctx
+			}(),
+			size: 2*layer + 2*16 + 1<<16,
+		},
+		{
+			title: "nested values",
+			ctx: func() context.Context {
+				ctx := context.Background()
+				ctx = context.WithValue(ctx, "", map[string][]byte{
+					"big": big,
+				})
+				ctx = context.WithValue(ctx, "", []interface{}{bigPointer, bigPointer})
+				return ctx
+			}(),
+			size: 2*layer + 2*16 + (48 + 16 + 24 + 1) + 3 + 1<<20 + 24 + 2*16 + 1<<16,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			size := Size(test.ctx)
+
+			assert.Equal(t, test.size, size)
+
+		})
+
+	}
+
+}
+
+func TestSizeChildren(t *testing.T) {
+
+	root, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	layer := int(reflect.TypeOf(root).Elem().Size())
+	assert.Equal(t, layer, Size(root))
+
+	for index := 0; index < 1000; index++ {
+		_, cancelChild := context.WithCancel(root)
+		defer cancelChild()
+	}
+
+	assert.Equal(t, layer, Size(root))
+
+}
+
+func TestSizeConcurrentCancel(t *testing.T) {
+
+	root, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		for index := 0; index < 1000; index++ {
+			_, cancelChild := context.WithCancel(root)
+			cancelChild()
+		}
+	}()
+
+	for index := 0; index < 1000; index++ {
+		Size(root)
+	}
+
+	wg.Wait()
+
+}
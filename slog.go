@@ -0,0 +1,25 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogValue returns a slog group value containing every key:value pair
+// contained within the context that has not been shadowed by a later pair
+// with the same key. Attributes are named with KeyName, and are ordered in
+// the order in which their values were added. Execution trace tasks are
+// rendered as a Task.
+func LogValue(ctx context.Context) slog.Value {
+	var attrs []slog.Attr
+
+	for _, pair := range live(Pairs(ctx)) {
+		attrs = append(attrs, slog.Any(KeyName(pair.Key), displayValue(pair.Value)))
+	}
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,57 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogValue(t *testing.T) {
+
+	Describe(describedKey{}, "described key", "a key used in tests")
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "key-1", "value-1")
+	ctx = context.WithValue(ctx, describedKey{}, 2)
+	ctx = context.WithValue(ctx, "key-1", "VALUE-ONE")
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+
+	logger.Info("message", "ctx", LogValue(ctx))
+
+	assert.Equal(t, `level=INFO msg=message "ctx.described key"=2 ctx.key-1=VALUE-ONE`+"\n", buf.String())
+	assert.Empty(t, LogValue(nil).Group())
+
+}
+
+func TestLogValueTraceTask(t *testing.T) {
+
+	ctx, task := NewTask(context.Background(), "checkout")
+	defer task.End()
+
+	info, found := TraceTask(ctx)
+	require.True(t, found)
+
+	attrs := LogValue(ctx).Group()
+	require.Len(t, attrs, 1)
+
+	assert.Equal(t, "trace task", attrs[0].Key)
+	assert.Equal(t, info, attrs[0].Value.Any())
+
+}
@@ -0,0 +1,274 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Layer describes the state of a single context layer, at the time that it
+// was captured by Snapshot.
+type Layer struct {
+	// Type is the name of the type implementing the layer, such as
+	// "*context.valueCtx".
+	Type string
+
+	// Key and Value hold the key:value pair of the layer, if HasKey is true.
+	Key    interface{}
+	Value  interface{}
+	HasKey bool
+
+	// Deadline holds the deadline of the layer, if HasDeadline is true.
+	Deadline    time.Time
+	HasDeadline bool
+
+	// Err and Cause hold the error and cause of the layer. For cancelable
+	// layers they are read from the layer itself, otherwise they are
+	// inherited from a parent.
+	Err   error
+	Cause error
+
+	// Cancelable is true if the layer can be canceled directly, such as those
+	// created by context.WithCancel, and Children holds the number of
+	// contexts that will be canceled along with it.
+	Cancelable bool
+	Children   int
+
+	// Timer holds the state of the layer's timer, if it is a deadline layer.
+	Timer TimerState
+}
+
+// ContextSnapshot is an immutable description of a context and all of its
+// layers, as returned by Snapshot.
+type ContextSnapshot struct {
+	// Layers contains every layer, in the order in which they were added.
+	Layers []Layer
+
+	// Pairs contains every key:value pair, in the order in which they were
+	// added.
+	Pairs []Pair
+
+	// Deadline, Err, and Cause hold the effective state of the context.
+	Deadline    time.Time
+	HasDeadline bool
+	Err         error
+	Cause       error
+
+	// Task holds the execution trace task of the context, if it has one.
+	Task    Task
+	HasTask bool
+}
+
+// Snapshot will return an immutable description of the given context. The
+// mutable state of each cancelable layer is read while holding that layer's
+// own lock, so it is safe to take a snapshot while other goroutines cancel
+// the context. A passed nil context will return an empty snapshot.
+//
+// Layers are captured from the outermost to the innermost. As cancellation
+// propagates from parents to children, a layer that was canceled by its
+// parent will never be captured with that parent appearing to not be
+// canceled. A layer that was canceled by its own cancel function may still
+// be captured with a parent that is not canceled.
+func Snapshot(ctx context.Context) ContextSnapshot {
+	var snapshot ContextSnapshot
+
+	// Guard against nil contexts
+	if ctx == nil {
+		return snapshot
+	}
+
+	snapshot.Deadline, snapshot.HasDeadline = ctx.Deadline()
+	snapshot.Task, snapshot.HasTask = TraceTask(ctx)
+
+	var (
+		layers   []Layer
+		inherits []bool
+	)
+	for _, layer := range chain(ctx) {
+		captured, inherit := captureLayer(layer)
+		layers = append(layers, captured)
+		inherits = append(inherits, inherit)
+	}
+
+	// Inherit errors from the parent, for layers that take their
+	// cancellation from it
+	for index := len(layers) - 1; index >= 0; index-- {
+		if inherits[index] && index+1 < len(layers) {
+			layers[index].Err = layers[index+1].Err
+			layers[index].Cause = layers[index+1].Cause
+		}
+	}
+
+	snapshot.Err = layers[0].Err
+	snapshot.Cause = layers[0].Cause
+
+	// Restore the order in which layers were added
+	for index := len(layers) - 1; index >= 0; index-- {
+		snapshot.Layers = append(snapshot.Layers, layers[index])
+		if layers[index].HasKey {
+			snapshot.Pairs = append(snapshot.Pairs, Pair{
+				Key:   layers[index].Key,
+				Value: layers[index].Value,
+			})
+		}
+	}
+
+	return snapshot
+}
+
+var (
+	mutexType       = reflect.TypeOf(sync.Mutex{})
+	atomicValueType = reflect.TypeOf(atomic.Value{})
+	valueCtxType    = reflect.TypeOf(context.WithValue(context.Background(), valueCtxKey{}, nil))
+)
+
+// valueCtxKey is used to obtain the type of the layers created by
+// context.WithValue.
+type valueCtxKey struct{}
+
+// captureLayer returns the state of a single layer, and if that layer takes
+// its errors from its parent, in which case they are not captured.
+func captureLayer(ctx context.Context) (Layer, bool) {
+	layer := Layer{
+		Type: reflect.TypeOf(ctx).String(),
+	}
+
+	layer.Deadline, layer.HasDeadline = ctx.Deadline()
+
+	if key, found := Key(ctx); found {
+		layer.Key = key
+		layer.Value = ctx.Value(key)
+		layer.HasKey = true
+	}
+
+	if info, ok := TimerInfo(ctx); ok {
+		layer.Timer = info.State
+	}
+
+	// Layers without their own lock are not cancelable. Those that are known
+	// to take their cancellation from their parent inherit its errors, while
+	// the errors of any other layer, such as one created by
+	// context.WithoutCancel, are read through its methods instead.
+	contextVal, unlock, ok := lockLayer(ctx)
+	if !ok {
+		if inheritsCancellation(ctx) && Unwrap(ctx) != nil {
+			return layer, true
+		}
+
+		// Causes are looked up through the nearest cancelable parent, so
+		// are only read if the layer itself reports an error
+		if layer.Err = ctx.Err(); layer.Err != nil {
+			layer.Cause = context.Cause(ctx)
+		}
+		return layer, false
+	}
+	defer unlock()
+
+	layer.Cancelable = true
+
+	if errField, ok := fieldByName(contextVal, "err"); ok {
+		layer.Err = readError(errField)
+	}
+
+	if causeField, ok := fieldByName(contextVal, "cause"); ok {
+		layer.Cause = readError(causeField)
+	}
+	if layer.Cause == nil {
+		layer.Cause = layer.Err
+	}
+
+	if childrenField, ok := fieldByName(contextVal, "children"); ok && childrenField.Kind() == reflect.Map {
+		layer.Children = childrenField.Len()
+	}
+
+	return layer, false
+}
+
+// inheritsCancellation reports if the given layer is known to take its
+// deadline and cancellation from its parent, without changing them.
+func inheritsCancellation(ctx context.Context) bool {
+	if _, ok := ctx.(*flatCtx); ok {
+		return true
+	}
+
+	return reflect.TypeOf(ctx) == valueCtxType
+}
+
+// cancelableTypes holds the types of the cancelable layers created by the
+// context package, such as by context.WithCancel, context.WithTimeout, and
+// context.AfterFunc.
+var cancelableTypes = func() map[reflect.Type]bool {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, cancelTimer := context.WithTimeout(ctx, time.Hour)
+	defer cancelTimer()
+
+	stop := context.AfterFunc(ctx, func() {})
+	defer stop()
+
+	types := map[reflect.Type]bool{
+		reflect.TypeOf(ctx): true,
+	}
+
+	// The layers created by context.AfterFunc are not returned, so every type
+	// is found among the children of the context instead
+	if contextVal, ok := layerStruct(ctx); ok {
+		if childrenField, ok := fieldByName(contextVal, "children"); ok && childrenField.Kind() == reflect.Map {
+			for _, child := range childrenField.MapKeys() {
+				types[child.Elem().Type()] = true
+			}
+		}
+	}
+
+	return types
+}()
+
+// lockLayer takes a cancelable layer, locks the mutex that guards its mutable
+// state, and returns the struct implementing it along with a function that
+// unlocks it again. Methods on the layer must not be called while it is
+// locked, as they may take the same lock. If the layer is not cancelable, no
+// lock is taken and false is returned.
+//
+// Only the cancelable layers created by the context package are locked, as
+// the fields of any other layer, even one with a similar "mu" field, can not
+// be known to hold the same state.
+func lockLayer(ctx context.Context) (reflect.Value, func(), bool) {
+	if ctx == nil || !cancelableTypes[reflect.TypeOf(ctx)] {
+		return reflect.Value{}, nil, false
+	}
+
+	contextVal, ok := layerStruct(ctx)
+	if !ok {
+		return reflect.Value{}, nil, false
+	}
+
+	muField, ok := fieldByName(contextVal, "mu")
+	if !ok || muField.Type() != mutexType {
+		return reflect.Value{}, nil, false
+	}
+
+	mu := (*sync.Mutex)(unsafe.Pointer(muField.UnsafeAddr()))
+	mu.Lock()
+
+	return contextVal, mu.Unlock, true
+}
+
+// readError reads an error from the given field, which may hold either an
+// error or an atomic.Value containing an error.
+func readError(field reflect.Value) error {
+	if field.Type() == atomicValueType {
+		err, _ := (*atomic.Value)(unsafe.Pointer(field.UnsafeAddr())).Load().(error)
+		return err
+	}
+
+	err, _ := readField(field).(error)
+	return err
+}
@@ -0,0 +1,253 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+
+	assert.Equal(t, ContextSnapshot{}, Snapshot(nil))
+
+	cause := errors.New("upstream shutdown")
+	deadline := time.Now().Add(time.Hour)
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, "key-1", "value-1")
+	ctx, cancel := context.WithCancelCause(ctx)
+	_, cancelChild := context.WithCancel(ctx)
+	defer cancelChild()
+	ctx, cancelDeadline := context.WithDeadline(ctx, deadline)
+	defer cancelDeadline()
+	ctx = context.WithValue(ctx, "key-2", "value-2")
+
+	snapshot := Snapshot(ctx)
+
+	assert.Equal(t, []Pair{{"key-1", "value-1"}, {"key-2", "value-2"}}, snapshot.Pairs)
+	assert.True(t, snapshot.HasDeadline)
+	assert.Equal(t, deadline, snapshot.Deadline)
+	assert.NoError(t, snapshot.Err)
+	assert.NoError(t, snapshot.Cause)
+
+	require.Len(t, snapshot.Layers, 5)
+	assert.Equal(t, "context.backgroundCtx", snapshot.Layers[0].Type)
+	assert.Equal(t, Layer{Type: "*context.valueCtx", Key: "key-1", Value: "value-1", HasKey: true}, snapshot.Layers[1])
+	assert.Equal(t, Layer{Type: "*context.cancelCtx", Cancelable: true, Children: 2}, snapshot.Layers[2])
+	assert.Equal(t, Layer{Type: "*context.timerCtx", Deadline: deadline, HasDeadline: true, Cancelable: true, Timer: TimerArmed}, snapshot.Layers[3])
+
+	cancel(cause)
+
+	snapshot = Snapshot(ctx)
+
+	assert.Equal(t, context.Canceled, snapshot.Err)
+	assert.Equal(t, cause, snapshot.Cause)
+
+	assert.NoError(t, snapshot.Layers[1].Err)
+	assert.Equal(t, Layer{Type: "*context.cancelCtx", Err: context.Canceled, Cause: cause, Cancelable: true}, snapshot.Layers[2])
+	assert.Equal(t, context.Canceled, snapshot.Layers[3].Err)
+	assert.Equal(t, cause, snapshot.Layers[3].Cause)
+	assert.Equal(t, TimerStopped, snapshot.Layers[3].Timer)
+	assert.Equal(t, cause, snapshot.Layers[4].Cause)
+
+}
+
+func TestSnapshotRoot(t *testing.T) {
+
+	ctx := Rebase(context.WithValue(context.Background(), "key", "value"), func() context.Context {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		return ctx
+	}())
+
+	snapshot := Snapshot(ctx)
+
+	assert.Equal(t, context.Canceled, snapshot.Err)
+	assert.Equal(t, context.Canceled, snapshot.Cause)
+	assert.Equal(t, []Pair{{"key", "value"}}, snapshot.Pairs)
+	assert.Equal(t, "*contents.cancelOnlyCtx", snapshot.Layers[0].Type)
+	assert.False(t, snapshot.Layers[0].Cancelable)
+
+}
+
+// detachedContext holds its parent's values, but is never canceled.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func TestSnapshotDetached(t *testing.T) {
+
+	EnableDiscovery(true)
+	defer EnableDiscovery(false)
+
+	cause := errors.New("upstream shutdown")
+
+	parent, cancel := context.WithCancelCause(context.Background())
+	cancel(cause)
+
+	tests := []struct {
+		title string
+		ctx   context.Context
+		typ   string
+	}{
+		{
+			title: "without cancel context",
+			ctx:   context.WithoutCancel(parent),
+			typ:   "context.withoutCancelCtx",
+		},
+		{
+			title: "custom context",
+			ctx:   detachedContext{parent},
+			typ:   "contents.detachedContext",
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			ctx := context.WithValue(test.ctx, "key", "value")
+
+			snapshot := Snapshot(ctx)
+
+			assert.NoError(t, snapshot.Err)
+			assert.NoError(t, snapshot.Cause)
+
+			require.Len(t, snapshot.Layers, 4)
+			assert.Equal(t, Layer{Type: "*context.cancelCtx", Err: context.Canceled, Cause: cause, Cancelable: true}, snapshot.Layers[1])
+			assert.Equal(t, Layer{Type: test.typ}, snapshot.Layers[2])
+			assert.NoError(t, snapshot.Layers[3].Err)
+			assert.NoError(t, snapshot.Layers[3].Cause)
+
+			causes := Causes(ctx)
+			require.Len(t, causes, 1)
+			assert.Equal(t, "*context.cancelCtx", causes[0].Type)
+
+		})
+
+	}
+
+}
+
+// mutexContext guards its own values with a mutex, in a field named like the
+// one used by cancelable contexts.
+type mutexContext struct {
+	context.Context
+	mu     sync.Mutex
+	values map[interface{}]interface{}
+}
+
+func (c *mutexContext) Value(key interface{}) interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if value, found := c.values[key]; found {
+		return value
+	}
+	return c.Context.Value(key)
+}
+
+func TestSnapshotMutexContext(t *testing.T) {
+
+	parent, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctx := &mutexContext{
+		Context: parent,
+		values:  map[interface{}]interface{}{"key": "value"},
+	}
+
+	snapshot := Snapshot(ctx)
+
+	assert.Equal(t, context.Canceled, snapshot.Err)
+	assert.Equal(t, context.Canceled, snapshot.Cause)
+
+	require.Len(t, snapshot.Layers, 3)
+	assert.Equal(t, Layer{Type: "*contents.mutexContext", Err: context.Canceled, Cause: context.Canceled}, snapshot.Layers[2])
+
+	causes := Causes(ctx)
+	require.Len(t, causes, 1)
+	assert.Equal(t, "*context.cancelCtx", causes[0].Type)
+
+	_, found := TimerInfo(ctx)
+	assert.False(t, found)
+	assert.Empty(t, layerChildren(ctx))
+	assert.Equal(t, []interface{}{"key"}, DiscoverKeys(ctx))
+
+}
+
+func TestSnapshotConcurrentCancel(t *testing.T) {
+
+	cause := errors.New("cause")
+
+	for attempt := 0; attempt < 50; attempt++ {
+
+		root, cancelRoot := context.WithCancelCause(context.Background())
+		ctx := context.WithValue(root, "key", "value")
+
+		var cancels []context.CancelFunc
+		for count := 0; count < 8; count++ {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Hour)
+			cancels = append(cancels, cancel)
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(len(cancels) + 2)
+
+		go func() {
+			defer wg.Done()
+			cancelRoot(cause)
+		}()
+
+		for _, cancel := range cancels {
+			go func(cancel context.CancelFunc) {
+				defer wg.Done()
+				cancel()
+			}(cancel)
+		}
+
+		go func() {
+			defer wg.Done()
+			for count := 0; count < 10; count++ {
+				snapshot := Snapshot(ctx)
+
+				// A layer canceled by the root is never captured along with
+				// a root that is not canceled
+				for _, layer := range snapshot.Layers {
+					if layer.Cause == cause {
+						assert.Equal(t, cause, snapshot.Layers[1].Cause)
+					}
+				}
+			}
+		}()
+
+		wg.Wait()
+
+		snapshot := Snapshot(ctx)
+		assert.Equal(t, context.Canceled, snapshot.Err)
+		assert.Equal(t, 0, snapshot.Layers[1].Children)
+
+	}
+
+}
@@ -0,0 +1,165 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// TimerState describes the state of the internal timer of a deadline layer,
+// such as those created by context.WithDeadline and context.WithTimeout.
+type TimerState int
+
+const (
+	// TimerNone means that the layer does not have a timer.
+	TimerNone TimerState = iota
+
+	// TimerArmed means that the timer is waiting for the deadline.
+	TimerArmed
+
+	// TimerFired means that the deadline was reached, and the layer was
+	// canceled with context.DeadlineExceeded.
+	TimerFired
+
+	// TimerStopped means that the layer was canceled before the deadline was
+	// reached, and the timer was stopped.
+	TimerStopped
+)
+
+func (s TimerState) String() string {
+	switch s {
+	case TimerArmed:
+		return "armed"
+	case TimerFired:
+		return "fired"
+	case TimerStopped:
+		return "stopped"
+	default:
+		return "none"
+	}
+}
+
+// Timer describes the deadline and internal timer of a deadline layer.
+type Timer struct {
+	Deadline  time.Time
+	Remaining time.Duration
+	State     TimerState
+}
+
+var timerType = reflect.TypeOf(&time.Timer{})
+
+// TimerInfo takes a context and returns the deadline and timer state of its
+// outermost layer, and if that layer is a deadline layer. A passed nil
+// context will return false.
+//
+// Once canceled, a deadline layer no longer holds a timer, so its state is
+// determined by its error. A layer canceled with context.DeadlineExceeded is
+// reported as fired, and any other layer as stopped.
+func TimerInfo(ctx context.Context) (Timer, bool) {
+	contextVal, unlock, ok := lockLayer(ctx)
+	if !ok {
+		return Timer{}, false
+	}
+	defer unlock()
+
+	timerField, ok := fieldByName(contextVal, "timer")
+	if !ok || timerField.Type() != timerType {
+		return Timer{}, false
+	}
+
+	deadlineField, ok := fieldByName(contextVal, "deadline")
+	if !ok {
+		return Timer{}, false
+	}
+
+	deadline, ok := readField(deadlineField).(time.Time)
+	if !ok {
+		return Timer{}, false
+	}
+
+	info := Timer{
+		Deadline:  deadline,
+		Remaining: time.Until(deadline),
+	}
+
+	var err error
+	if errField, ok := fieldByName(contextVal, "err"); ok {
+		err = readError(errField)
+	}
+
+	switch {
+	case !timerField.IsNil():
+		info.State = TimerArmed
+	case errors.Is(err, context.DeadlineExceeded):
+		info.State = TimerFired
+	case err != nil:
+		info.State = TimerStopped
+	}
+
+	return info, true
+}
+
+// ArmedTimers will return the number of armed timers held by the given context
+// and every context derived from it. Derived contexts are found through the
+// nearest cancelable layer of the given context, so contexts derived from a
+// sibling that shares that layer are also counted. A passed nil context will
+// return 0.
+func ArmedTimers(ctx context.Context) int {
+	var count int
+
+	// Find the nearest cancelable layer
+	queue := []context.Context{}
+	for _, layer := range chain(ctx) {
+		if _, unlock, ok := lockLayer(layer); ok {
+			unlock()
+			queue = append(queue, layer)
+			break
+		}
+	}
+
+	for len(queue) > 0 {
+		layer := queue[0]
+		queue = queue[1:]
+
+		if info, ok := TimerInfo(layer); ok && info.State == TimerArmed {
+			count++
+		}
+
+		for _, child := range layerChildren(layer) {
+			if child, ok := child.(context.Context); ok {
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	return count
+}
+
+// layerChildren returns the children of the given cancelable layer, which are
+// the cancelable contexts that will be canceled along with it.
+func layerChildren(ctx context.Context) []interface{} {
+	contextVal, unlock, ok := lockLayer(ctx)
+	if !ok {
+		return nil
+	}
+	defer unlock()
+
+	childrenField, ok := fieldByName(contextVal, "children")
+	if !ok || childrenField.Kind() != reflect.Map || childrenField.IsNil() {
+		return nil
+	}
+
+	var children []interface{}
+
+	iter := reflect.ValueOf(readField(childrenField)).MapRange()
+	for iter.Next() {
+		children = append(children, iter.Key().Interface())
+	}
+
+	return children
+}
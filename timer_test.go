@@ -0,0 +1,146 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimerInfo(t *testing.T) {
+
+	deadline := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		title string
+		ctx   context.Context
+		state TimerState
+		found bool
+	}{
+		{
+			title: "nil context",
+			ctx:   nil,
+		},
+		{
+			title: "background context",
+			ctx:   context.Background(),
+		},
+		{
+			title: "cancel context",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				_ = cancel
+				return ctx
+			}(),
+		},
+		{
+			title: "value context over deadline context",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithDeadline(context.Background(), deadline)
+				_ = cancel
+				return context.WithValue(ctx, "key", "value")
+			}(),
+		},
+		{
+			title: "armed deadline context",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithDeadline(context.Background(), deadline)
+				_ = cancel
+				return ctx
+			}(),
+			state: TimerArmed,
+			found: true,
+		},
+		{
+			title: "stopped deadline context",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithDeadline(context.Background(), deadline)
+				cancel()
+				return ctx
+			}(),
+			state: TimerStopped,
+			found: true,
+		},
+		{
+			title: "stopped by parent",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				ctx, cancelDeadline := context.WithDeadline(ctx, deadline)
+				_ = cancelDeadline
+				cancel()
+				return ctx
+			}(),
+			state: TimerStopped,
+			found: true,
+		},
+		{
+			title: "fired deadline context",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+				_ = cancel
+				<-ctx.Done()
+				return ctx
+			}(),
+			state: TimerFired,
+			found: true,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			info, found := TimerInfo(test.ctx)
+
+			assert.Equal(t, test.found, found)
+			assert.Equal(t, test.state, info.State)
+
+			if test.state == TimerArmed {
+				assert.Equal(t, deadline, info.Deadline)
+				assert.True(t, info.Remaining > 0 && info.Remaining <= time.Hour)
+			}
+
+		})
+
+	}
+
+}
+
+func TestArmedTimers(t *testing.T) {
+
+	root, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.Equal(t, 0, ArmedTimers(nil))
+	assert.Equal(t, 0, ArmedTimers(context.Background()))
+	assert.Equal(t, 0, ArmedTimers(root))
+
+	ctx := context.WithValue(root, "key", "value")
+
+	first, cancelFirst := context.WithTimeout(ctx, time.Hour)
+	_, cancelSecond := context.WithTimeout(first, time.Minute)
+	_, cancelThird := context.WithTimeout(ctx, time.Hour)
+	defer cancelThird()
+
+	assert.Equal(t, 3, ArmedTimers(ctx))
+	assert.Equal(t, 2, ArmedTimers(first))
+
+	cancelSecond()
+
+	assert.Equal(t, 2, ArmedTimers(root))
+	assert.Equal(t, 1, ArmedTimers(first))
+
+	cancelFirst()
+
+	assert.Equal(t, 1, ArmedTimers(root))
+	assert.Equal(t, 0, ArmedTimers(first))
+
+}
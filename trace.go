@@ -0,0 +1,120 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/trace"
+	"sync"
+)
+
+var traceTaskKey interface{}
+
+func init() {
+	// Tasks are stored by trace.NewTask under a private key, so one is
+	// obtained from a context holding a task in order to describe it
+	ctx, task := trace.NewTask(context.Background(), "")
+	task.End()
+
+	if key, found := Key(ctx); found {
+		traceTaskKey = key
+		Describe(key, "trace task", "execution trace task set by trace.NewTask")
+	}
+}
+
+// Task describes an execution trace task, as created by trace.NewTask. Name
+// is empty unless the task was created with NewTask.
+type Task struct {
+	ID   uint64
+	Name string
+}
+
+// String returns the name and ID of the task, such as "checkout (id 3)".
+func (t Task) String() string {
+	if t.Name == "" {
+		return fmt.Sprintf("task %d", t.ID)
+	}
+	return fmt.Sprintf("%s (id %d)", t.Name, t.ID)
+}
+
+// taskNames maps the ID of a task created with NewTask to its name. Tasks are
+// not kept alive by this table, and their entries are removed once they are
+// collected.
+var taskNames sync.Map
+
+// NewTask is equivalent to trace.NewTask, but records the name of the task so
+// that it can be reported by TraceTask.
+func NewTask(parent context.Context, taskType string) (context.Context, *trace.Task) {
+	ctx, task := trace.NewTask(parent, taskType)
+
+	if info, ok := traceTaskOf(task); ok {
+		taskNames.Store(info.ID, taskType)
+		runtime.AddCleanup(task, func(id uint64) {
+			taskNames.Delete(id)
+		}, info.ID)
+	}
+
+	return ctx, task
+}
+
+// TraceTask returns the execution trace task attached to the given context
+// with trace.NewTask, and if such a task exists. A passed nil context will
+// return false.
+//
+// The name of a task is only known if it was created with NewTask, as
+// trace.NewTask does not retain it.
+func TraceTask(ctx context.Context) (Task, bool) {
+	// Guard against nil contexts
+	if ctx == nil || traceTaskKey == nil {
+		return Task{}, false
+	}
+
+	task, ok := ctx.Value(traceTaskKey).(*trace.Task)
+	if !ok {
+		return Task{}, false
+	}
+
+	return traceTaskOf(task)
+}
+
+// traceTaskOf returns a description of the given task, by reading its "id"
+// field, and if that field exists.
+func traceTaskOf(task *trace.Task) (Task, bool) {
+	taskVal, ok := layerStruct(task)
+	if !ok {
+		return Task{}, false
+	}
+
+	idField, ok := fieldByName(taskVal, "id")
+	if !ok {
+		return Task{}, false
+	}
+
+	id, ok := readField(idField).(uint64)
+	if !ok {
+		return Task{}, false
+	}
+
+	info := Task{ID: id}
+	if name, found := taskNames.Load(id); found {
+		info.Name = name.(string)
+	}
+
+	return info, true
+}
+
+// displayValue returns the given context value in a form suitable for
+// display, by replacing execution trace tasks with their description.
+func displayValue(value interface{}) interface{} {
+	if task, ok := value.(*trace.Task); ok {
+		if info, ok := traceTaskOf(task); ok {
+			return info
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,129 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"runtime/trace"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceTask(t *testing.T) {
+
+	tests := []struct {
+		title string
+		ctx   func() (context.Context, *trace.Task)
+		name  string
+		found bool
+	}{
+		{
+			title: "nil context",
+			ctx: func() (context.Context, *trace.Task) {
+				return nil, nil
+			},
+		},
+		{
+			title: "background context",
+			ctx: func() (context.Context, *trace.Task) {
+				return context.Background(), nil
+			},
+		},
+		{
+			title: "unnamed task",
+			ctx: func() (context.Context, *trace.Task) {
+				return trace.NewTask(context.Background(), "checkout")
+			},
+			found: true,
+		},
+		{
+			title: "named task",
+			ctx: func() (context.Context, *trace.Task) {
+				ctx, task := NewTask(context.Background(), "checkout")
+				ctx = context.WithValue(ctx, "key", "value")
+				return ctx, task
+			},
+			name:  "checkout",
+			found: true,
+		},
+		{
+			title: "nested task",
+			ctx: func() (context.Context, *trace.Task) {
+				ctx, parent := NewTask(context.Background(), "request")
+				defer parent.End()
+				return NewTask(ctx, "query")
+			},
+			name:  "query",
+			found: true,
+		},
+	}
+
+	for index, test := range tests {
+
+		name := fmt.Sprintf("case #%d - %s", index, test.title)
+
+		t.Run(name, func(t *testing.T) {
+
+			ctx, task := test.ctx()
+			if task != nil {
+				defer task.End()
+			}
+
+			info, found := TraceTask(ctx)
+
+			assert.Equal(t, test.found, found)
+			assert.Equal(t, test.name, info.Name)
+
+			if found {
+				assert.NotZero(t, info.ID)
+			}
+
+		})
+
+	}
+
+}
+
+func TestTraceTaskUnique(t *testing.T) {
+
+	first, firstTask := NewTask(context.Background(), "first")
+	defer firstTask.End()
+	second, secondTask := NewTask(context.Background(), "second")
+	defer secondTask.End()
+
+	firstInfo, found := TraceTask(first)
+	require.True(t, found)
+	secondInfo, found := TraceTask(second)
+	require.True(t, found)
+
+	assert.NotEqual(t, firstInfo.ID, secondInfo.ID)
+	assert.Equal(t, fmt.Sprintf("first (id %d)", firstInfo.ID), firstInfo.String())
+	assert.Equal(t, fmt.Sprintf("task %d", firstInfo.ID), Task{ID: firstInfo.ID}.String())
+
+}
+
+func TestTraceTaskDisplay(t *testing.T) {
+
+	ctx, task := NewTask(context.Background(), "checkout")
+	defer task.End()
+
+	info, found := TraceTask(ctx)
+	require.True(t, found)
+
+	snapshot := Snapshot(ctx)
+	assert.True(t, snapshot.HasTask)
+	assert.Equal(t, info, snapshot.Task)
+
+	pairs := Pairs(ctx)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, "trace task", KeyName(pairs[0].Key))
+	assert.Equal(t, info, displayValue(pairs[0].Value))
+
+	assert.Equal(t, "value", displayValue("value"))
+
+}
@@ -0,0 +1,71 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+)
+
+// TypedPair is a Pair whose key and value have known types.
+type TypedPair[K, V any] struct {
+	Key   K
+	Value V
+}
+
+// KeysOf will return every key contained within the context that is of type
+// K, in the order in which they were originally added. If K is an interface
+// type, every key that implements K is returned.
+func KeysOf[K any](ctx context.Context) []K {
+	var keys []K
+
+	for _, key := range Keys(ctx) {
+		if typed, ok := key.(K); ok {
+			keys = append(keys, typed)
+		}
+	}
+
+	return keys
+}
+
+// ValuesOf will return every value contained within the context that is of
+// type V, in the order in which they were originally added. If V is an
+// interface type, every value that implements V is returned.
+func ValuesOf[V any](ctx context.Context) []V {
+	var values []V
+
+	for _, pair := range Pairs(ctx) {
+		if typed, ok := pair.Value.(V); ok {
+			values = append(values, typed)
+		}
+	}
+
+	return values
+}
+
+// PairsWhere will return every key:value pair contained within the context
+// whose key is of type K and whose value is of type V, in the order in which
+// they were originally added.
+func PairsWhere[K, V any](ctx context.Context) []TypedPair[K, V] {
+	var pairs []TypedPair[K, V]
+
+	for _, pair := range Pairs(ctx) {
+		key, ok := pair.Key.(K)
+		if !ok {
+			continue
+		}
+
+		value, ok := pair.Value.(V)
+		if !ok {
+			continue
+		}
+
+		pairs = append(pairs, TypedPair[K, V]{
+			Key:   key,
+			Value: value,
+		})
+	}
+
+	return pairs
+}
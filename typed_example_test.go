@@ -0,0 +1,40 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joshdk/contents"
+)
+
+type featureKey string
+
+func ExampleKeysOf() {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, featureKey("dark-mode"), true)
+	ctx = context.WithValue(ctx, "user", "alice")
+	ctx = context.WithValue(ctx, featureKey("beta"), false)
+
+	for _, key := range contents.KeysOf[featureKey](ctx) {
+		fmt.Printf("Feature %q is %v\n", key, ctx.Value(key))
+	}
+	// Output:
+	// Feature "dark-mode" is true
+	// Feature "beta" is false
+}
+
+func ExamplePairsWhere() {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, featureKey("dark-mode"), true)
+	ctx = context.WithValue(ctx, featureKey("retries"), 3)
+
+	for _, pair := range contents.PairsWhere[featureKey, bool](ctx) {
+		fmt.Printf("Feature %q is %v\n", pair.Key, pair.Value)
+	}
+	// Output:
+	// Feature "dark-mode" is true
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 Josh Komoroske. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE.txt file.
+
+package contents
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typedKey string
+
+func TestTyped(t *testing.T) {
+
+	err := fmt.Errorf("error")
+
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, typedKey("key-1"), "value-1")
+	ctx = context.WithValue(ctx, "key-2", 2)
+	ctx = context.WithValue(ctx, typedKey("key-3"), 3)
+	ctx = context.WithValue(ctx, 4, err)
+	ctx = context.WithValue(ctx, typedKey("key-1"), "VALUE-ONE")
+
+	assert.Equal(t, []typedKey{"key-1", "key-3", "key-1"}, KeysOf[typedKey](ctx))
+	assert.Equal(t, []string{"key-2"}, KeysOf[string](ctx))
+	assert.Equal(t, []interface{}{typedKey("key-1"), "key-2", typedKey("key-3"), 4, typedKey("key-1")}, KeysOf[interface{}](ctx))
+	assert.Nil(t, KeysOf[bool](ctx))
+
+	assert.Equal(t, []string{"value-1", "VALUE-ONE"}, ValuesOf[string](ctx))
+	assert.Equal(t, []int{2, 3}, ValuesOf[int](ctx))
+	assert.Equal(t, []error{err}, ValuesOf[error](ctx))
+	assert.Nil(t, ValuesOf[bool](ctx))
+
+	assert.Equal(t, []TypedPair[typedKey, string]{
+		{"key-1", "value-1"},
+		{"key-1", "VALUE-ONE"},
+	}, PairsWhere[typedKey, string](ctx))
+	assert.Equal(t, []TypedPair[typedKey, interface{}]{
+		{"key-1", "value-1"},
+		{"key-3", 3},
+		{"key-1", "VALUE-ONE"},
+	}, PairsWhere[typedKey, interface{}](ctx))
+	assert.Nil(t, PairsWhere[string, string](ctx))
+
+	assert.Nil(t, KeysOf[typedKey](nil))
+	assert.Nil(t, ValuesOf[string](nil))
+	assert.Nil(t, PairsWhere[typedKey, string](nil))
+
+}